@@ -0,0 +1,186 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+)
+
+/*
+Sum, Avg, Min and Max compute an aggregate of a column or SQL expression for
+the records of the Scope, placing the result into the dest pointer. When there
+are no records, or the values are all NULL, dest is set to its zero value,
+unless it is a pointer to a pointer or a sql.Scanner like sql.NullInt64, which
+will be able to tell that the result was NULL. A limited Scope aggregates only
+the records within the limit, while a Scope with a GroupBy needs the grouped
+aggregates below.
+
+  var total int64
+  Posts.EqualTo("user_id", userId).Sum("views", &total)
+
+  var newest time.Time
+  Posts.Max("created_at", &newest)
+*/
+func (q *queryable) Sum(column string, dest interface{}) error {
+	return q.aggregate("Sum", "SUM", column, dest)
+}
+
+// Avg places the average of the column for the Scope's records into dest
+func (q *queryable) Avg(column string, dest interface{}) error {
+	return q.aggregate("Avg", "AVG", column, dest)
+}
+
+// Min places the smallest value of the column for the Scope's records into dest
+func (q *queryable) Min(column string, dest interface{}) error {
+	return q.aggregate("Min", "MIN", column, dest)
+}
+
+// Max places the largest value of the column for the Scope's records into dest
+func (q *queryable) Max(column string, dest interface{}) error {
+	return q.aggregate("Max", "MAX", column, dest)
+}
+
+/*
+CountBy, SumBy, AvgBy, MinBy and MaxBy compute an aggregate for each group of a
+Scope with a GroupBy, filling the map pointed at by dest with the aggregate keyed
+by the value of the GroupBy column. The GroupBy should be a single column.
+
+  // number of posts for each user
+  counts := map[int]int64{}
+  Posts.GroupBy("user_id").CountBy(&counts)
+
+  // total views for each category
+  var views map[string]float64
+  Posts.GroupBy("category").SumBy("views", &views)
+*/
+func (q *queryable) CountBy(dest interface{}) error {
+	return q.groupedAggregate("CountBy", "COUNT("+q.tableAlias()+"."+q.source.ID.SqlColumn+")", dest)
+}
+
+// SumBy fills the dest map with the sum of the column for each group
+func (q *queryable) SumBy(column string, dest interface{}) error {
+	return q.groupedAggregate("SumBy", "SUM("+q.aggregateColumn(column)+")", dest)
+}
+
+// AvgBy fills the dest map with the average of the column for each group
+func (q *queryable) AvgBy(column string, dest interface{}) error {
+	return q.groupedAggregate("AvgBy", "AVG("+q.aggregateColumn(column)+")", dest)
+}
+
+// MinBy fills the dest map with the smallest value of the column for each group
+func (q *queryable) MinBy(column string, dest interface{}) error {
+	return q.groupedAggregate("MinBy", "MIN("+q.aggregateColumn(column)+")", dest)
+}
+
+// MaxBy fills the dest map with the largest value of the column for each group
+func (q *queryable) MaxBy(column string, dest interface{}) error {
+	return q.groupedAggregate("MaxBy", "MAX("+q.aggregateColumn(column)+")", dest)
+}
+
+// aggregateColumn qualifies plain column names with the Scope's table
+func (q *queryable) aggregateColumn(column string) string {
+	if isIdentifier(column) {
+		return q.tableAlias() + "." + column
+	}
+	return column
+}
+
+// aggregateScope is the Scope used to select an aggregate, orderings and
+// locks are removed since they can't be used with ungrouped columns. Sum,
+// Avg, Min and Max select from a subquery instead when the Scope is limited.
+func (q *queryable) aggregateScope(selection ...selector) *queryable {
+	qq := q.Identity().(*queryable)
+	qq.selection = selection
+	qq.order = nil
+	qq.lock = nil
+	return qq
+}
+
+func (q *queryable) aggregate(method, function, column string, dest interface{}) error {
+	if err := q.readErr(); err != nil {
+		return err
+	}
+	if q.groupBy != "" {
+		return errors.New(method + " can't aggregate a GroupBy Scope, use " + method + "By")
+	}
+	if reflect.TypeOf(dest).Kind() != reflect.Ptr {
+		return errors.New("Must Supply Ptr to Destination")
+	}
+
+	qq := q.aggregateScope(selector{Formula: function + "(" + q.aggregateColumn(column) + ")"})
+	if q.limit != 0 || q.offset != 0 {
+		// the limit is for the records that are aggregated, so the column
+		// is selected from the limited records in a subquery
+		lq := q.Identity().(*queryable)
+		lq.lock = nil
+		lq.selection = []selector{selector{Type: formula, Formula: q.aggregateColumn(column), Alias: "aggregated"}}
+		qq = &queryable{
+			source:    q.source,
+			ctx:       q.ctx,
+			primary:   q.primary,
+			selection: []selector{selector{Formula: function + "(aggregated)"}},
+			from:      &fromTable{lq, "limited"},
+		}
+	}
+	query, values := qq.source.conn.Dialect.Query(qq)
+	row := qq.source.runReadRow(qq.runContext(method), qq.primary, query, values)
+
+	if _, ok := dest.(sql.Scanner); ok || reflect.TypeOf(dest).Elem().Kind() == reflect.Ptr {
+		return row.Scan(dest)
+	}
+	nullable := reflect.New(reflect.TypeOf(dest))
+	if err := row.Scan(nullable.Interface()); err != nil {
+		return err
+	}
+	setNullable(reflect.ValueOf(dest).Elem(), nullable.Elem())
+	return nil
+}
+
+func (q *queryable) groupedAggregate(method, formula string, dest interface{}) error {
+	if err := q.readErr(); err != nil {
+		return err
+	}
+	if q.groupBy == "" {
+		return errors.New(method + " needs a GroupBy Scope")
+	}
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Ptr || destVal.Elem().Kind() != reflect.Map {
+		return errors.New("Must Supply Ptr to Map")
+	}
+
+	qq := q.aggregateScope(selector{Formula: q.groupBy}, selector{Formula: formula})
+	query, values := qq.source.conn.Dialect.Query(qq)
+	rows, err := qq.source.runRead(qq.runContext(method), qq.primary, query, values)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	mapVal := destVal.Elem()
+	if mapVal.IsNil() {
+		mapVal.Set(reflect.MakeMap(mapVal.Type()))
+	}
+	keyType, valType := mapVal.Type().Key(), mapVal.Type().Elem()
+	for rows.Next() {
+		key := reflect.New(reflect.PtrTo(keyType))
+		val := reflect.New(reflect.PtrTo(valType))
+		if err = rows.Scan(key.Interface(), val.Interface()); err != nil {
+			return err
+		}
+		k, v := reflect.New(keyType).Elem(), reflect.New(valType).Elem()
+		setNullable(k, key.Elem())
+		setNullable(v, val.Elem())
+		mapVal.SetMapIndex(k, v)
+	}
+	return rows.Err()
+}
+
+// setNullable sets dest from a pointer that was scanned into, dest is set
+// to its zero value when the pointer is nil because the value was NULL
+func setNullable(dest, nullable reflect.Value) {
+	if nullable.IsNil() {
+		dest.Set(reflect.Zero(dest.Type()))
+	} else {
+		dest.Set(nullable.Elem())
+	}
+}
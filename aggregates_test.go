@@ -0,0 +1,76 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+
+	. "github.com/acsellers/assert"
+)
+
+func TestAggregates(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+
+			test.Section("Sum, Avg, Min and Max")
+			var total int64
+			test.NoError(Posts.Sum("views", &total))
+			test.AreEqual(2, total)
+			var avg float64
+			test.NoError(Posts.Avg("views", &avg))
+			test.AreEqual(1, avg)
+			var first string
+			test.NoError(Posts.Min("title", &first))
+			test.AreEqual("First Post", first)
+			var last int
+			test.NoError(Posts.Order("id").Max("id", &last))
+			test.AreEqual(2, last)
+
+			test.Section("Aggregates of no records")
+			total = 5
+			test.NoError(Posts.EqualTo("id", 0).Sum("views", &total))
+			test.AreEqual(0, total)
+			var nullable sql.NullInt64
+			test.NoError(Posts.EqualTo("id", 0).Max("views", &nullable))
+			test.IsFalse(nullable.Valid)
+			var ptr *int64
+			test.NoError(Posts.EqualTo("id", 0).Max("views", &ptr))
+			test.IsTrue(ptr == nil)
+
+			test.Section("Aggregates of limited records")
+			test.NoError(Posts.Order("id").Limit(1).Sum("id", &total))
+			test.AreEqual(1, total)
+			test.NoError(Posts.Order("id").Limit(1).Offset(1).Min("id", &last))
+			test.AreEqual(2, last)
+			test.IsNotNil(Posts.GroupBy("user_id").Sum("views", &total))
+
+			test.Section("Grouped aggregates")
+			counts := map[int]int64{}
+			test.NoError(Posts.GroupBy("user_id").CountBy(&counts))
+			test.AreEqual(map[int]int64{0: 1, 1: 1}, counts)
+			var views map[int]float64
+			test.NoError(Posts.GroupBy("user_id").Having("SUM(views) > ?", 0).SumBy("views", &views))
+			test.AreEqual(2, len(views))
+			var titles map[int]string
+			test.NoError(Posts.Where("user_id = ?", 1).GroupBy("posts.user_id").MaxBy("title", &titles))
+			test.AreEqual(map[int]string{1: "First Post"}, titles)
+
+			test.Section("Grouped aggregates need a GroupBy")
+			test.IsNotNil(Posts.CountBy(&counts))
+			test.IsNotNil(Posts.GroupBy("user_id").CountBy(counts))
+		}
+	})
+}
+
+func TestExpandGroupBy(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+			scope := Posts.Columns("user_id", Func("COUNT(*)"), "title").GroupBy("user_id").(*queryable)
+			test.AreEqual("user_id, posts.title", scope.groupBySql())
+			scope = Posts.Columns("user_id", "title").GroupBy("posts.user_id, title").(*queryable)
+			test.AreEqual("posts.user_id, title", scope.groupBySql())
+			test.AreEqual([]string{"a", "COUNT(b, c)", "'x,y'"}, splitColumns("a, COUNT(b, c), 'x,y'"))
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// throughRelation finds the has and belongs to many relation named by the
+// struct field name
+func (s *source) throughRelation(relation string) (*sourceMapping, error) {
+	for _, r := range s.relations {
+		if r.structOptions.Name == relation {
+			if r.Through == nil {
+				return nil, fmt.Errorf("%s.%s isn't a has and belongs to many relation", s.Name, relation)
+			}
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("Couldn't find a relation named %s for %s", relation, s.Name)
+}
+
+// associationKeys returns the primary key of the instance and of each
+// related record, records may be structs, pointers to structs or keys
+func (s *source) associationKeys(sm *sourceMapping, instance interface{}, records []interface{}) (interface{}, []interface{}, error) {
+	key := s.extractID(reflect.Indirect(reflect.ValueOf(instance)))
+	keys := make([]interface{}, len(records))
+	for i, record := range records {
+		rv := reflect.Indirect(reflect.ValueOf(record))
+		if rv.Kind() == reflect.Struct {
+			keys[i] = sm.Relation.extractID(rv)
+		} else {
+			keys[i] = record
+		}
+	}
+	for _, k := range append([]interface{}{key}, keys...) {
+		if kv := reflect.ValueOf(k); !kv.IsValid() || reflect.Zero(kv.Type()).Interface() == k {
+			return nil, nil, errors.New("Can't associate records that haven't been saved")
+		}
+	}
+	return key, keys, nil
+}
+
+// associate inserts a row into the join table of the relation for each of
+// the records, linking them to the instance
+func (s *source) associate(ctx context.Context, instance interface{}, relation string, records []interface{}) error {
+	sm, err := s.throughRelation(relation)
+	if err != nil {
+		return err
+	}
+	key, keys, err := s.associationKeys(sm, instance, records)
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+
+	jt := sm.Through
+	rows := make([]string, len(keys))
+	values := make([]interface{}, 0, 2*len(keys))
+	for i, k := range keys {
+		rows[i] = "(?, ?)"
+		values = append(values, key, k)
+	}
+	query := "INSERT INTO " + jt.Table + " (" + jt.Key + ", " + jt.ForeignKey + ") VALUES " + strings.Join(rows, ", ")
+	ctx = withOrigin(ctx, &origin{source: s, method: "Associate"})
+	_, err = s.runExec(ctx, s.conn.Dialect.FormatQuery(query), values)
+	return err
+}
+
+// dissociate deletes the join table rows linking the records to the
+// instance, or every row for the instance when no records are passed
+func (s *source) dissociate(ctx context.Context, instance interface{}, relation string, records []interface{}) error {
+	sm, err := s.throughRelation(relation)
+	if err != nil {
+		return err
+	}
+	key, keys, err := s.associationKeys(sm, instance, records)
+	if err != nil {
+		return err
+	}
+
+	jt := sm.Through
+	query := "DELETE FROM " + jt.Table + " WHERE " + jt.Key + " = ?"
+	values := []interface{}{key}
+	if len(keys) > 0 {
+		holders := make([]string, len(keys))
+		for i := range holders {
+			holders[i] = "?"
+		}
+		query += " AND " + jt.ForeignKey + " IN (" + strings.Join(holders, ", ") + ")"
+		values = append(values, keys...)
+	}
+	ctx = withOrigin(ctx, &origin{source: s, method: "Dissociate"})
+	_, err = s.runUpdate(ctx, s.conn.Dialect.FormatQuery(query), values)
+	return err
+}
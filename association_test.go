@@ -0,0 +1,137 @@
+package db
+
+import (
+	"testing"
+
+	. "github.com/acsellers/assert"
+)
+
+func TestManyToMany(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Articles := c.m("Article")
+			Labels := c.m("Label")
+
+			test.Section("Join table")
+			var labels *sourceMapping
+			for _, r := range Articles.(*source).relations {
+				if r.structOptions.Name == "Labels" {
+					labels = r
+				}
+			}
+			test.IsNotNil(labels)
+			if labels != nil {
+				test.IsNotNil(labels.Through)
+				if labels.Through != nil {
+					test.AreEqual("articles_labels", labels.Through.Table)
+					test.AreEqual("article_id", labels.Through.Key)
+					test.AreEqual("label_id", labels.Through.ForeignKey)
+				}
+			}
+			sql, _ := Articles.InnerJoin(Labels).JoinsSql()
+			test.AreEqual(" INNER JOIN articles_labels ON articles_labels.article_id = articles.id INNER JOIN labels ON labels.id = articles_labels.label_id", sql)
+
+			test.Section("Associate")
+			var a article
+			Articles.Initialize(&a)
+			a.Title = "Many to Many"
+			test.NoError(a.Save())
+			var golang, databases label
+			Labels.Initialize(&golang)
+			Labels.Initialize(&databases)
+			golang.Name, databases.Name = "golang", "databases"
+			test.NoError(golang.Save())
+			test.NoError(databases.Save())
+			test.NoError(a.Associate("Labels", &golang, databases))
+			test.IsNotNil(a.Associate("Title", &golang))
+			var unsaved label
+			test.IsNotNil(a.Associate("Labels", &unsaved))
+
+			test.Section("Preload")
+			var articles []article
+			test.NoError(Articles.Preload("Labels").RetrieveAll(&articles))
+			test.AreEqual(1, len(articles))
+			if len(articles) == 1 {
+				test.AreEqual(2, len(articles[0].Labels))
+			}
+			var l label
+			test.NoError(Labels.Preload("Articles").Find(golang.Id, &l))
+			test.AreEqual(1, len(l.Articles))
+			if len(l.Articles) == 1 {
+				test.AreEqual("Many to Many", l.Articles[0].Title)
+			}
+
+			test.Section("Include")
+			articles = nil
+			test.NoError(Articles.LeftInclude(Labels).RetrieveAll(&articles))
+			test.AreEqual(1, len(articles))
+			if len(articles) == 1 {
+				test.AreEqual(2, len(articles[0].Labels))
+			}
+			count, err := Articles.InnerJoin(Labels).EqualTo("labels.name", "golang").Count()
+			test.NoError(err)
+			test.AreEqual(1, count)
+
+			test.Section("Dissociate")
+			test.NoError(a.Dissociate("Labels", golang.Id))
+			articles = nil
+			test.NoError(Articles.Preload("Labels").RetrieveAll(&articles))
+			if len(articles) == 1 && len(articles[0].Labels) == 1 {
+				test.AreEqual("databases", articles[0].Labels[0].Name)
+			}
+			test.NoError(a.Dissociate("Labels"))
+			count, err = Articles.InnerJoin(Labels).Count()
+			test.NoError(err)
+			test.AreEqual(0, count)
+
+			test.NoError(a.Delete())
+			test.NoError(golang.Delete())
+			test.NoError(databases.Delete())
+		}
+	})
+}
+
+type friend struct {
+	Id      int
+	Friends []friend
+}
+
+func TestJoinTableTags(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Members := c.m("Member")
+
+			test.Section("Join table tags")
+			sql, _ := Members.InnerJoin("Friends").JoinsSql()
+			test.AreEqual(" INNER JOIN friendships AS friends_friendships ON friends_friendships.member_id = members.id INNER JOIN members AS friends ON friends.id = friends_friendships.friend_id", sql)
+
+			var alice, bob, carol member
+			Members.Initialize(&alice)
+			Members.Initialize(&bob)
+			Members.Initialize(&carol)
+			alice.Name, bob.Name, carol.Name = "alice", "bob", "carol"
+			test.NoError(alice.Save())
+			test.NoError(bob.Save())
+			test.NoError(carol.Save())
+			test.NoError(alice.Associate("Friends", &bob, &carol))
+			test.NoError(bob.Associate("Friends", &alice))
+
+			var m member
+			test.NoError(Members.Preload("Friends").Find(alice.Id, &m))
+			test.AreEqual(2, len(m.Friends))
+			test.NoError(Members.Preload("Friends").Find(carol.Id, &m))
+			test.AreEqual(0, len(m.Friends))
+			ct, e := Members.InnerJoin("Friends").EqualTo("friends.name", "alice").Count()
+			test.NoError(e)
+			test.AreEqual(1, ct)
+
+			test.NoError(alice.Dissociate("Friends"))
+			test.NoError(bob.Dissociate("Friends"))
+			test.NoError(Members.Where("id > ?", 0).Delete())
+
+			test.Section("Relations to the same struct need key tags")
+			_, e = c.CreateMapper("Friend", &friend{})
+			test.IsNotNil(e)
+		}
+	})
+}
@@ -50,11 +50,35 @@ func (d Base) CreateExec() bool {
 
 // Create a basic SELECT query using ScopeInformation functions
 func (d Base) Query(scope Scope) (string, []interface{}) {
-	output := "SELECT " + scope.SelectorSql() + " FROM " + scope.TableName()
-	output += scope.JoinsSql()
-	conditions, values := scope.ConditionSql()
+	output, values := selectSql(scope)
+	lock, err := d.Dialect.Lock(scope)
+	if err != nil {
+		// the terminal functions return the error before building the
+		// query, the clause is kept so the query can't run without its lock
+		lock = scope.LockSql()
+	}
+	if lock != "" {
+		output += " " + lock
+	}
+	return d.Dialect.FormatQuery(output), values
+}
+
+// selectSql creates the SELECT query for a Scope without formatting it,
+// so that it can be used as a subquery within another query
+func selectSql(scope Scope) (string, []interface{}) {
+	output, values := scope.WithSql()
+	selection, selectValues := scope.SelectorSql()
+	values = append(values, selectValues...)
+	from, fromValues := scope.FromSql()
+	output += "SELECT " + selection + " FROM " + from
+	values = append(values, fromValues...)
+	joins, joinValues := scope.JoinsSql()
+	output += joins
+	values = append(values, joinValues...)
+	conditions, condValues := scope.ConditionSql()
 	if conditions != "" {
 		output += " WHERE " + conditions
+		values = append(values, condValues...)
 	}
 	ending, endValues := scope.EndingSql()
 	if len(endValues) > 0 {
@@ -62,7 +86,7 @@ func (d Base) Query(scope Scope) (string, []interface{}) {
 	}
 	output += ending
 
-	return d.Dialect.FormatQuery(output), values
+	return output, values
 }
 
 // The Base Create function uses the syntax of INSERT INTO `table` (col...) VALUES (...)
@@ -153,3 +177,68 @@ func (d Base) printArg(v interface{}) string {
 func (d Base) ExpandGroupBy() bool {
 	return true
 }
+
+// The Base Savepoint uses the SAVEPOINT name syntax, which is shared by
+// mysql, postgres and sqlite
+func (d Base) Savepoint(name string) string {
+	return "SAVEPOINT " + name
+}
+
+// The Base ReleaseSavepoint uses the RELEASE SAVEPOINT name syntax
+func (d Base) ReleaseSavepoint(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
+
+// The Base RollbackToSavepoint uses the ROLLBACK TO SAVEPOINT name syntax
+func (d Base) RollbackToSavepoint(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+// The Base RetryableError doesn't know about any driver's errors, so
+// it returns false
+func (d Base) RetryableError(err error) bool {
+	return false
+}
+
+// The Base Explain prefixes the query with EXPLAIN, or EXPLAIN ANALYZE
+// when analyze is true
+func (d Base) Explain(query string, analyze bool) (string, error) {
+	if analyze {
+		return "EXPLAIN ANALYZE " + query, nil
+	}
+	return "EXPLAIN " + query, nil
+}
+
+// The Base ParsePlan makes a PlanNode for each row returned, with the
+// values of the row as the Type and Detail of the PlanNode
+func (d Base) ParsePlan(rows *sql.Rows) (*QueryPlan, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	results, err := scanStrings(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &QueryPlan{}
+	for _, result := range results {
+		values := make([]string, 0, len(columns))
+		for _, column := range columns {
+			values = append(values, result[strings.ToLower(column)])
+		}
+		detail := strings.Join(values, " ")
+		plan.Nodes = append(plan.Nodes, &PlanNode{Type: detail, Detail: detail})
+	}
+	return plan, nil
+}
+
+// The Base Lock uses the LockSql of the Scope as it is
+func (d Base) Lock(scope Scope) (string, error) {
+	return scope.LockSql(), nil
+}
+
+// The Base SetOperation allows UNION, UNION ALL, INTERSECT and EXCEPT
+func (d Base) SetOperation(op string) error {
+	return nil
+}
@@ -9,6 +9,9 @@ import (
 
 func holderFor(v interface{}) string {
 	switch va := v.(type) {
+	case Scope:
+		query, _ := selectSql(va)
+		return "(" + query + ")"
 	case SqlFunc:
 		return va.Fragment()
 	case SqlCol:
@@ -22,6 +25,9 @@ func valuesFor(v ...interface{}) []interface{} {
 	out := []interface{}{}
 	for _, arg := range v {
 		switch at := arg.(type) {
+		case Scope:
+			_, sv := selectSql(at)
+			out = append(out, sv...)
 		case SqlFunc:
 			fv := at.Values()
 			if len(fv) > 0 {
@@ -81,6 +87,8 @@ type SqlFunc interface {
 	// A version of Fragment with the parameters inside that
 	// is suitable for logging.
 	String() string
+	// Over makes a window function from the SQL function, see Window
+	Over(partition, order string) *Window
 }
 
 // Interface for a SQL Column that you can use in Queryable
@@ -131,6 +139,9 @@ type andCondition struct {
 }
 
 func (ac *andCondition) String() string {
+	if nested := ac.nested(); nested != nil {
+		return nested.String()
+	}
 	conds := make([]string, len(ac.conditions))
 	for i, condition := range ac.conditions {
 		conds[i] = condition.String()
@@ -138,6 +149,9 @@ func (ac *andCondition) String() string {
 	return "(" + strings.Join(conds, " AND ") + ")"
 }
 func (ac *andCondition) Fragment() string {
+	if nested := ac.nested(); nested != nil {
+		return nested.Fragment()
+	}
 	conds := make([]string, len(ac.conditions))
 	for i, condition := range ac.conditions {
 		conds[i] = condition.Fragment()
@@ -155,6 +169,32 @@ func (ac *andCondition) Values() []interface{} {
 	return vals
 }
 
+// nested is the only condition when it is already parenthesized, so that it
+// isn't wrapped in a second set of parentheses
+func (ac *andCondition) nested() condition {
+	if len(ac.conditions) == 1 {
+		switch ac.conditions[0].(type) {
+		case *andCondition, *orCondition:
+			return ac.conditions[0]
+		}
+	}
+	return nil
+}
+
+type notCondition struct {
+	condition condition
+}
+
+func (nc *notCondition) String() string {
+	return "NOT " + nc.condition.String()
+}
+func (nc *notCondition) Fragment() string {
+	return "NOT " + nc.condition.Fragment()
+}
+func (nc *notCondition) Values() []interface{} {
+	return nc.condition.Values()
+}
+
 type betweenCondition struct {
 	column       string
 	lower, upper interface{}
@@ -174,6 +214,10 @@ func newInCondition(column string, items interface{}) *inCondition {
 	ic := &inCondition{
 		column: column,
 	}
+	if scope, ok := items.(Scope); ok {
+		ic.items = []interface{}{scope}
+		return ic
+	}
 	rv := reflect.ValueOf(items)
 	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
 		for i := 0; i < rv.Len(); i++ {
@@ -193,14 +237,19 @@ func (ic *inCondition) String() string {
 	return withVars(ic.Fragment(), ic.Values())
 }
 func (ic *inCondition) Fragment() string {
+	if len(ic.items) == 1 {
+		if _, ok := ic.items[0].(Scope); ok {
+			return ic.column + " IN " + holderFor(ic.items[0])
+		}
+	}
 	places := make([]string, len(ic.items))
-	for i, _ := range places {
-		places[i] = "?"
+	for i, item := range ic.items {
+		places[i] = holderFor(item)
 	}
 	return ic.column + " IN (" + strings.Join(places, ", ") + ")"
 }
 func (ic *inCondition) Values() []interface{} {
-	return ic.items
+	return valuesFor(ic.items...)
 }
 
 type equalCondition struct {
@@ -213,11 +262,14 @@ func (ec *equalCondition) String() string {
 }
 func (ec *equalCondition) Fragment() string {
 	if isNil(ec.val) {
-		return ec.column + "IS NULL"
+		return ec.column + " IS NULL"
 	}
 	return ec.column + " = " + holderFor(ec.val)
 }
 func (ec *equalCondition) Values() []interface{} {
+	if isNil(ec.val) {
+		return []interface{}{}
+	}
 	return valuesFor(ec.val)
 }
 
@@ -227,6 +279,9 @@ type whereCondition struct {
 }
 
 func (wc *whereCondition) String() string {
+	if wc.hasSubquery() {
+		return withVars(wc.Fragment(), wc.Values())
+	}
 	switch {
 	case len(wc.args) == 0:
 		return wc.fragment
@@ -237,13 +292,22 @@ func (wc *whereCondition) String() string {
 	}
 }
 func (wc *whereCondition) Fragment() string {
+	fragment, _ := spliceSubqueries(wc.unsplicedFragment(), wc.unsplicedValues())
+	return fragment
+}
+func (wc *whereCondition) Values() []interface{} {
+	_, values := spliceSubqueries(wc.unsplicedFragment(), wc.unsplicedValues())
+	return values
+}
+
+func (wc *whereCondition) unsplicedFragment() string {
 	if len(wc.args) == 1 && isBindVars(wc.args[0]) {
 		return unbind(wc.fragment)
 	}
 
 	return wc.fragment
 }
-func (wc *whereCondition) Values() []interface{} {
+func (wc *whereCondition) unsplicedValues() []interface{} {
 	switch {
 	case len(wc.args) == 0:
 		return []interface{}{}
@@ -253,6 +317,53 @@ func (wc *whereCondition) Values() []interface{} {
 
 	return wc.args
 }
+func (wc *whereCondition) hasSubquery() bool {
+	for _, v := range wc.unsplicedValues() {
+		if _, ok := v.(Scope); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// spliceSubqueries replaces the ?'s that have Scopes as their values with
+// the SQL for the Scopes, and the Scopes with their values. Parentheses are
+// added around the SQL unless the ? is already wrapped in them.
+func spliceSubqueries(fragment string, values []interface{}) (string, []interface{}) {
+	parts := strings.Split(fragment, "?")
+	if len(parts) == 1 {
+		return fragment, values
+	}
+
+	output := parts[0]
+	spliced := make([]interface{}, 0, len(values))
+	for i, part := range parts[1:] {
+		if i >= len(values) {
+			output += "?" + part
+			continue
+		}
+		scope, ok := values[i].(Scope)
+		if !ok {
+			output += "?" + part
+			spliced = append(spliced, values[i])
+			continue
+		}
+
+		query, sv := selectSql(scope)
+		before := strings.TrimRight(output, " ")
+		after := strings.TrimLeft(part, " ")
+		if strings.HasSuffix(before, "(") && strings.HasPrefix(after, ")") {
+			output += query + part
+		} else {
+			output += "(" + query + ")" + part
+		}
+		spliced = append(spliced, sv...)
+	}
+	if len(values) > len(parts)-1 {
+		spliced = append(spliced, values[len(parts)-1:]...)
+	}
+	return output, spliced
+}
 
 type varyCondition struct {
 	column string
@@ -261,28 +372,7 @@ type varyCondition struct {
 }
 
 func (vc *varyCondition) String() string {
-	switch vc.cond {
-	case EQUAL:
-		if isNil(vc.val) {
-			return vc.column + " IS NULL"
-		}
-		return vc.column + " = " + printString(vc.val)
-	case NOT_EQUAL:
-		if isNil(vc.val) {
-			return vc.column + " IS NOT NULL"
-		}
-		return vc.column + " <> " + printString(vc.val)
-	case LESS_THAN:
-		return vc.column + " < " + printString(vc.val)
-	case LESS_OR_EQUAL:
-		return vc.column + " <= " + printString(vc.val)
-	case GREATER_THAN:
-		return vc.column + " > " + printString(vc.val)
-	case GREATER_OR_EQUAL:
-		return vc.column + " >= " + printString(vc.val)
-	}
-
-	return ""
+	return withVars(vc.Fragment(), vc.Values())
 }
 
 func (vc *varyCondition) Fragment() string {
@@ -291,20 +381,20 @@ func (vc *varyCondition) Fragment() string {
 		if isNil(vc.val) {
 			return vc.column + " IS NULL"
 		}
-		return vc.column + " = ?"
+		return vc.column + " = " + holderFor(vc.val)
 	case NOT_EQUAL:
 		if isNil(vc.val) {
 			return vc.column + " IS NOT NULL"
 		}
-		return vc.column + " <> ?"
+		return vc.column + " <> " + holderFor(vc.val)
 	case LESS_THAN:
-		return vc.column + " < ?"
+		return vc.column + " < " + holderFor(vc.val)
 	case LESS_OR_EQUAL:
-		return vc.column + " <= ?"
+		return vc.column + " <= " + holderFor(vc.val)
 	case GREATER_THAN:
-		return vc.column + " > ?"
+		return vc.column + " > " + holderFor(vc.val)
 	case GREATER_OR_EQUAL:
-		return vc.column + " >= ?"
+		return vc.column + " >= " + holderFor(vc.val)
 	}
 
 	return ""
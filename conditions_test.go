@@ -49,6 +49,11 @@ func TestEqualCondition(t *testing.T) {
 		test.Section("Test Equal Condition Log")
 		test.AreEqual(ec.String(), "test_tbl.test_col = 1")
 
+		test.Section("Test Equal Condition NULL")
+		ec = &equalCondition{"test_tbl.test_col", nil}
+		test.AreEqual(ec.Fragment(), "test_tbl.test_col IS NULL")
+		test.AreEqual(0, len(ec.Values()))
+
 		ec.val = "asdf"
 		test.AreEqual(ec.String(), "test_tbl.test_col = 'asdf'")
 	})
@@ -185,3 +190,22 @@ func TestValuesFor(t *testing.T) {
 		test.AreEqual([]interface{}{5}, valuesFor(Func("NOW(?)", 5)))
 	})
 }
+
+func TestWindow(t *testing.T) {
+	Within(t, func(test *Test) {
+		test.Section("Window SQL")
+		w := Func("ROW_NUMBER()").Over("user_id", "id DESC")
+		test.AreEqual("ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY id DESC)", w.Fragment())
+
+		test.Section("Framed Window")
+		w = Func("SUM(views * ?)", 2).Over("", "id").Rows("UNBOUNDED PRECEDING", "CURRENT ROW")
+		test.AreEqual("SUM(views * ?) OVER (ORDER BY id ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)", w.Fragment())
+		test.AreEqual([]interface{}{2}, w.Values())
+		test.AreEqual("SUM(views * 2) OVER (ORDER BY id ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)", w.String())
+
+		test.Section("Copies")
+		aliased := w.As("total")
+		test.AreEqual("", w.Alias)
+		test.AreEqual("total", aliased.Alias)
+	})
+}
@@ -26,6 +26,13 @@ type Config struct {
 	// the database column next
 	IdName func(structName string) (string, string)
 
+	// A function that gives the name of the join table for a has and
+	// belongs to many relation from the table names of the two structs,
+	// for instance Rails would take in "posts" and "tags" and return
+	// "posts_tags". When this isn't set, the table names are sorted and
+	// joined with an underscore.
+	JoinTableName func(table, otherTable string) string
+
 	// When timestamping is added, CreatedColumn is the default column
 	// to set to the current time when creating records in the database
 	CreatedColumn string
@@ -34,3 +41,18 @@ type Config struct {
 	// Update* calls would not update this.
 	UpdatedColumn string
 }
+
+func (c *Config) joinTableName(table, otherTable string) string {
+	if c.JoinTableName != nil {
+		return c.JoinTableName(table, otherTable)
+	}
+	return sortedJoinTable(table, otherTable)
+}
+
+// sortedJoinTable joins the table names in alphabetical order
+func sortedJoinTable(table, otherTable string) string {
+	if otherTable < table {
+		table, otherTable = otherTable, table
+	}
+	return table + "_" + otherTable
+}
@@ -1,10 +1,12 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
 	"strconv"
+	"time"
 
 	"github.com/dchest/cache"
 )
@@ -39,6 +41,29 @@ type Connection struct {
 	// By default, it will store up to 4096 distinct queries, you can use the
 	// CacheSize(n int) to change the query storage number
 	QueryCache *cache.Cache
+	// The RetryPolicy for re-running operations that fail from deadlocks and
+	// similar errors, by default this is nil and nothing will be retried
+	RetryPolicy *RetryPolicy
+	// Statements that take longer than the SlowQueryThreshold are recorded
+	// in the SlowQueries report, by default this is zero and nothing will be
+	// recorded
+	SlowQueryThreshold time.Duration
+	// The NPlusOne detector reports statements that are run over and over
+	// with different keys in a unit of work, by default this is nil and
+	// statements won't be watched
+	NPlusOne *NPlusOneDetector
+	// The number of keys sent in each query made by Preload, by default
+	// this is zero and 500 keys are sent at a time
+	PreloadBatchSize int
+	tx               *sql.Tx
+	parent           *Connection
+	savepoint        string
+	savepoints       *int
+	driverName       string
+	replicas         *replicaSet
+	slowQueries      *slowQueryLog
+	beforeHooks      []BeforeHook
+	afterHooks       []AfterHook
 }
 
 /*
@@ -67,30 +92,53 @@ func NewConnection(dialectName, dbName, connector string) (*Connection, error) {
 	conn.mappedStructs = make(map[string]*source)
 	conn.mappableStructs = make(map[string][]*source)
 	conn.sources = make(map[string]*source)
-	conn.QueryCache = cache.New(cache.Config{
-		MaxItems:        4096,
+	conn.driverName = dialectName
+	conn.replicas = new(replicaSet)
+	conn.QueryCache = newQueryCache(4096)
+	conn.slowQueries = newSlowQueryLog()
+
+	return conn, nil
+}
+
+func queryCacheConfig(n int) cache.Config {
+	return cache.Config{
+		MaxItems:        n,
 		TrackAccessTime: true,
 		RemoveHandler: func(p cache.Item) {
 			if q, ok := p.Value.(*sql.Stmt); ok {
 				q.Close()
 			}
 		},
-	})
+	}
+}
 
-	return conn, nil
+func newQueryCache(n int) *cache.Cache {
+	return cache.New(queryCacheConfig(n))
 }
 
 // A Connection can be closed, which essentially means that the
 // *sql.DB connection is closed, though it should not be counted
 // on that the Close operation will not clear out other structures
-// or will clear out other structures.
+// or will clear out other structures. Closing a Connection returned
+// by Begin will rollback the transaction if it is still open, but
+// will leave the database connection open.
 func (c *Connection) Close() error {
+	if c.parent != nil {
+		if c.tx != nil {
+			return c.Rollback()
+		}
+		return nil
+	}
+	if c.replicas != nil {
+		c.replicas.close()
+	}
 	return c.DB.Close()
 }
 
 // A Logger is a struct that has a subset of a log.Logger, you
 // can use a log.Logger for it, but you can substitute a different
-// struct of your own imagining if you wish.
+// struct of your own imagining if you wish. Every statement the
+// Connection runs will be logged as a *LogEntry.
 func (c *Connection) SetLogger(logger Logger, logType int) {
 	switch logType {
 	case LOG_ALL:
@@ -265,12 +313,25 @@ func (c *Connection) createSqlMappings(s *source) {
 //
 //  rows, e := pgConn.Query(bigQuery, values...)
 func (c *Connection) Query(query string, args ...interface{}) (*sql.Rows, error) {
-	stmt, e := c.getQuery(query)
+	return c.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext is Query with a context that can cancel the query
+// or set a deadline for it.
+//
+//  rows, e := pgConn.QueryContext(req.Context(), bigQuery, values...)
+func (c *Connection) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	var rows *sql.Rows
+	stmt, cached, e := c.getQuery(ctx, query)
 	if e == nil {
-		return stmt.Query(args...)
+		rows, e = stmt.QueryContext(ctx, args...)
 	} else {
-		return c.DB.Query(query, args...)
+		rows, e = c.executor().QueryContext(ctx, query, args...)
 	}
+	c.finishStatement(ctx, query, args, start, cached, nil, e)
+
+	return rows, e
 }
 
 // This is almost the same as Connection.DB.QueryRow(query, args), but will
@@ -279,12 +340,25 @@ func (c *Connection) Query(query string, args ...interface{}) (*sql.Rows, error)
 //
 //  row := pgConn.QueryRow(complicated, values...)
 func (c *Connection) QueryRow(query string, args ...interface{}) *sql.Row {
-	stmt, e := c.getQuery(query)
+	return c.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext is QueryRow with a context that can cancel the query
+// or set a deadline for it.
+//
+//  row := pgConn.QueryRowContext(req.Context(), complicated, values...)
+func (c *Connection) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	start := time.Now()
+	var row *sql.Row
+	stmt, cached, e := c.getQuery(ctx, query)
 	if e == nil {
-		return stmt.QueryRow(args...)
+		row = stmt.QueryRowContext(ctx, args...)
 	} else {
-		return c.DB.QueryRow(query, args...)
+		row = c.executor().QueryRowContext(ctx, query, args...)
 	}
+	c.finishStatement(ctx, query, args, start, cached, nil, row.Err())
+
+	return row
 }
 
 // This is almost the same as Connection.DB.Exec(query, args), but will
@@ -293,45 +367,126 @@ func (c *Connection) QueryRow(query string, args ...interface{}) *sql.Row {
 //
 //  result, e := pgConn.Exec(createThings, values...)
 func (c *Connection) Exec(query string, args ...interface{}) (sql.Result, error) {
-	stmt, e := c.getQuery(query)
+	return c.ExecContext(context.Background(), query, args...)
+}
+
+// ExecContext is Exec with a context that can cancel the statement
+// or set a deadline for it.
+//
+//  result, e := pgConn.ExecContext(req.Context(), createThings, values...)
+func (c *Connection) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	var result sql.Result
+	stmt, cached, e := c.getQuery(ctx, query)
 	if e == nil {
-		return stmt.Exec(args...)
+		result, e = stmt.ExecContext(ctx, args...)
 	} else {
-		return c.DB.Exec(query, args...)
+		result, e = c.executor().ExecContext(ctx, query, args...)
 	}
+	c.finishStatement(ctx, query, args, start, cached, result, e)
+
+	return result, e
 }
 
 // Set the number of queries that may be present in the query cache
 // at any time. Default is 4096 for the arbitrary reason of I like
-// that number.
+// that number. The query caches of any replicas will be resized
+// as well.
 func (c *Connection) CacheSize(n int) {
-	c.QueryCache.Reconfigure(cache.Config{
-		MaxItems:        n,
-		TrackAccessTime: true,
-		RemoveHandler: func(p cache.Item) {
-			if q, ok := p.Value.(*sql.Stmt); ok {
-				q.Close()
-			}
-		},
-	})
+	c.QueryCache.Reconfigure(queryCacheConfig(n))
+	if c.replicas != nil {
+		c.replicas.resize(n)
+	}
+}
+
+func (c *Connection) getQuery(ctx context.Context, query string) (*sql.Stmt, bool, error) {
+	q, cached, e := getQuery(ctx, c.DB, c.QueryCache, query)
+	if e != nil {
+		return nil, false, e
+	}
+
+	return c.inTx(ctx, q), cached, nil
 }
 
-func (c *Connection) getQuery(query string) (*sql.Stmt, error) {
-	if c.QueryCache == nil {
-		return nil, fmt.Errorf("QueryCache not enabled")
+// getQuery retrieves the prepared statement for a query from the query cache,
+// or prepares it on the database and adds it to the cache. The bool is true
+// when the statement was found in the cache.
+func getQuery(ctx context.Context, db *sql.DB, qc *cache.Cache, query string) (*sql.Stmt, bool, error) {
+	if qc == nil {
+		return nil, false, fmt.Errorf("QueryCache not enabled")
 	}
 
-	i, ok := c.QueryCache.Get(query)
+	i, ok := qc.Get(query)
 	if ok {
 		if q, ok := i.(*sql.Stmt); ok {
-			return q, nil
+			return q, true, nil
 		}
 	}
-	q, e := c.DB.Prepare(query)
+	q, e := db.PrepareContext(ctx, query)
 	if e != nil {
-		return nil, e
+		return nil, false, e
 	}
-	c.QueryCache.Set(query, q, 0)
+	qc.Set(query, q, 0)
+
+	return q, false, nil
+}
 
-	return q, nil
+// finishStatement is called after each statement is run with the time it
+// was started, so the statement can be logged and recorded if it was slow.
+// result is nil for queries.
+func (c *Connection) finishStatement(ctx context.Context, query string, args []interface{}, start time.Time, cached bool, result sql.Result, err error) {
+	d := time.Since(start)
+	if c.SlowQueryThreshold > 0 && d >= c.SlowQueryThreshold && err == nil {
+		c.slowQueries.record(originOf(ctx), query, args, d)
+	}
+	if !c.logging() {
+		return
+	}
+
+	entry := &LogEntry{
+		Query:        query,
+		Args:         args,
+		Duration:     d,
+		RowsAffected: -1,
+		Cached:       cached,
+		Err:          err,
+	}
+	if result != nil && err == nil {
+		if n, e := result.RowsAffected(); e == nil {
+			entry.RowsAffected = n
+		}
+	}
+	c.logStatement(entry)
+}
+
+// inTx will return the transaction specific version of a cached
+// statement when the Connection is in a transaction
+func (c *Connection) inTx(ctx context.Context, q *sql.Stmt) *sql.Stmt {
+	if tx := c.currentTx(); tx != nil {
+		return tx.StmtContext(ctx, q)
+	}
+	return q
+}
+
+// currentTx returns the transaction queries should be run on, once a
+// nested transaction has finished, that is the enclosing transaction
+func (c *Connection) currentTx() *sql.Tx {
+	if c.tx == nil && c.savepoint != "" {
+		return c.parent.currentTx()
+	}
+	return c.tx
+}
+
+// an executor is the *sql.DB or *sql.Tx that un-cached queries are run on
+type executor interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func (c *Connection) executor() executor {
+	if tx := c.currentTx(); tx != nil {
+		return tx
+	}
+	return c.DB
 }
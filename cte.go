@@ -0,0 +1,108 @@
+package db
+
+import (
+	"strings"
+)
+
+// A commonTable is a named Scope in the WITH clause of a query, recursive
+// common tables are the anchor Scope combined with the recursive Scope
+type commonTable struct {
+	name      string
+	anchor    Scope
+	recursive Scope
+}
+
+func (ct *commonTable) Fragment() string {
+	query, _ := selectSql(ct.anchor)
+	if ct.recursive != nil {
+		recursive, _ := selectSql(ct.recursive)
+		query += " UNION ALL " + recursive
+	}
+	return ct.name + " AS (" + query + ")"
+}
+func (ct *commonTable) Values() []interface{} {
+	_, vals := selectSql(ct.anchor)
+	if ct.recursive != nil {
+		_, recursive := selectSql(ct.recursive)
+		vals = append(vals, recursive...)
+	}
+	return vals
+}
+func (ct *commonTable) String() string {
+	return withVars(ct.Fragment(), ct.Values())
+}
+
+/*
+With adds a common table expression to the query for the Scope, the name can
+then be used like a table in the Scope, in From, JoinSql, Where or in Scopes
+used as subqueries.
+
+  // find the posts of users that signed up this week
+  Posts.With("new_users", Users.Cond("created_at", db.GTE, weekStart)).
+    JoinSql("INNER JOIN new_users ON new_users.id = posts.user_id")
+*/
+func (q *queryable) With(name string, cte Scope) Scope {
+	nq := q.Identity().(*queryable)
+	nq.addCommonTables([]*commonTable{&commonTable{name: name, anchor: cte}})
+	nq.useSubqueries(cte)
+	return nq
+}
+
+/*
+WithRecursive adds a recursive common table expression to the query for the
+Scope. The rows of the anchor Scope are combined with the rows of the recursive
+Scope, which refers to the name to find the rows related to the rows found so
+far.
+
+  // find a comment and all of its replies
+  Comments.WithRecursive(
+    "thread",
+    Comments.EqualTo("id", commentId),
+    Comments.JoinSql("INNER JOIN thread ON comments.parent_id = thread.id"),
+  ).From("thread", "")
+*/
+func (q *queryable) WithRecursive(name string, anchor, recursive Scope) Scope {
+	nq := q.Identity().(*queryable)
+	nq.addCommonTables([]*commonTable{&commonTable{name: name, anchor: anchor, recursive: recursive}})
+	nq.useSubqueries(anchor, recursive)
+	return nq
+}
+
+// WithSql is the WITH clause for the common tables of the Scope, it includes
+// the trailing space so it can be put directly before the SELECT
+func (q *queryable) WithSql() (string, []interface{}) {
+	if len(q.with) == 0 {
+		return "", []interface{}{}
+	}
+
+	output := "WITH "
+	for _, ct := range q.with {
+		if ct.recursive != nil {
+			output = "WITH RECURSIVE "
+		}
+	}
+	tables := make([]string, len(q.with))
+	vals := []interface{}{}
+	for i, ct := range q.with {
+		tables[i] = ct.Fragment()
+		vals = append(vals, ct.Values()...)
+	}
+	return output + strings.Join(tables, ", ") + " ", vals
+}
+
+// addCommonTables adds common tables to the Scope, replacing any common
+// tables with the same name
+func (q *queryable) addCommonTables(tables []*commonTable) {
+	for _, ct := range tables {
+		replaced := false
+		for i, existing := range q.with {
+			if existing.name == ct.name {
+				q.with[i] = ct
+				replaced = true
+			}
+		}
+		if !replaced {
+			q.with = append(q.with, ct)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"database/sql"
 	"reflect"
 )
 
@@ -59,4 +60,29 @@ type Dialect interface {
 	// them, but we need to know whether we need to do it for this database
 	// system
 	ExpandGroupBy() bool
+	// Nested transactions are run using savepoints, these functions return
+	// the sql to create a savepoint, release a savepoint when the nested
+	// transaction is committed, and rollback to a savepoint when it is
+	// rolled back.
+	Savepoint(name string) string
+	ReleaseSavepoint(name string) string
+	RollbackToSavepoint(name string) string
+	// RetryableError classifies errors returned by the database driver,
+	// errors like deadlocks or serialization failures that would succeed
+	// if the statement or transaction was run again should return true
+	RetryableError(err error) bool
+	// Explain wraps a query from Query so that it returns the plan for the
+	// query instead of the results, if analyze is true the plan should
+	// include the actual rows and times from running the query. Databases
+	// that can't explain the query should return an error.
+	Explain(query string, analyze bool) (string, error)
+	// ParsePlan reads the rows returned by an Explain query into a QueryPlan
+	ParsePlan(rows *sql.Rows) (*QueryPlan, error)
+	// SetOperation returns an error when the database can't run a set
+	// operation, which will be one of UNION, UNION ALL, INTERSECT or EXCEPT
+	SetOperation(op string) error
+	// Lock returns the row locking clause that goes after the query for a
+	// Scope, usually the Scope's LockSql, or an error if the database can't
+	// lock the rows read by the Scope
+	Lock(scope Scope) (string, error)
 }
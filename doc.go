@@ -57,9 +57,24 @@ Start building an environment by calling NewConnection with you dialect
 name ("mysql", "sqlite", "postgres") the name of the database to
 use, and the connector string for the database adapter (document this).
 
-There is code to manage Transactions using Connections, this isn't well
-supported and should be removed if it is not well supported or
-semi-supported until EphemeralConnections come around.
+Transactions
+
+Calling Begin on a Connection starts a transaction and returns an
+EphemeralConnection, which is a *Connection that runs all of its queries
+on the transaction. Existing Mappers, MapperPlus's and Scopes are moved onto
+the EphemeralConnection with its Mapper and Scope functions, and instances
+retrieved through them will Save, Delete and UpdateAttribute(s) inside of the
+transaction. The Transaction function wraps this up, committing when the
+passed function returns nil and rolling back on an error or panic.
+
+  err := conn.Transaction(func(tx *db.Connection) error {
+    TxPosts := tx.Mapper(Posts)
+    e := TxPosts.SaveAll(newPosts)
+    if e != nil {
+      return e
+    }
+    return tx.Scope(oldPosts).UpdateAttribute("archived", true)
+  })
 
 Mappers
 
@@ -248,8 +263,6 @@ is then mapped into a simple array value that was passed as the second value.
   var emails []string
   Users.Joins(Payments).Where("payments.month = ? AND payments.paid_on IS NULL", month).Pluck("email", &emails)
 
-Possible Future Retrieval Methods
-
 The CountOn method is a user controlled version of Count. If you would like to specify
 a specific column, perhaps to do a DISTINCT count on, this is what you want.
 
@@ -265,12 +278,14 @@ of pointers to the arrays for the data.
   Users.
     Joins(Payments).
     Where("payments.month = ? AND payments.paid_on IS NOT NULL", month).
-    Pluck([]string{"name", "email"}, &names, &emails)
+    PluckSeveral([]string{"name", "email"}, &names, &emails)
 
 The Select function allows you to map specially selected columns and/or formulas into
 purpose-written or anonymous structs. If a table has many columns, or you are returning
 quite a bit of data, this can be a performance boost to use special structs instead of the
-default mapper.
+default mapper. Columns are matched to fields using the FieldToColumn function of the
+Connection's Config, formulas need to be given an alias to be matched. Select can also
+take a pointer to a single struct, which will be filled from the first row.
 
   // get weekly newsletter readers
   type weeklyReaders struct {
@@ -279,7 +294,7 @@ default mapper.
   }
   var readers []weeklyReaders
 
-  columns := "users.name, users.email, GROUP_CONCAT(subscription_sections.name SEPARATOR '|')"
+  columns := "users.name, users.email, GROUP_CONCAT(subscription_sections.name SEPARATOR '|') AS sections"
   Users.Joins(Subscriptions).Joins(SubscriptionSections).GroupBy("users.id").Select(columns, &readers)
 
 
@@ -387,17 +402,19 @@ instead, you can use the OuterJoin function.
   // retrieve the posts from a specific author, that have featured comments
   Posts.EqualTo("Author", theAuthor).Join(Comments).EqualTo("comments.featured", true)
 
-Possible Future Join Enhancements
+Unmatched Records
 
-The FullJoin function allows you to retrieve records that don't have a match to the primary
-mapped struct. You pass the normal Join paramerters, but add a pointer to an array of the
-struct you are asking to be joined, which will be filled with the non-matching records when
-the first Retrieve/RetrieveAll call is made
+The FullInclude and RightInclude functions allow you to retrieve records that don't have a
+match to the primary mapped struct. You pass the normal Include parameter, along with a
+pointer to a slice of the struct you are asking to be included, which will be filled with
+the non-matching records when Retrieve or RetrieveAll is called. Mysql doesn't have full
+outer joins, so they are run as a left join combined with a right join of the missing
+records, and the orderings of the Scope can only use the columns of the primary mapper.
 
   // find all author's posts, also get one's that are missing an author
   var authors []User
   var orphaned []Post
-  User.FullJoin(&orphaned, Posts).Cond("posts.created_at", GTE, oneYearAgo).RetrieveAll(&authors)
+  Users.FullInclude(Posts, &orphaned).RetrieveAll(&authors)
 
 SQL Sundries
 
@@ -430,7 +447,27 @@ accessible under and an instance of your dialect struct.
 
 Logging
 
-Before a public announcement of a db version, I need to implement the Logging facilities.
-It won't be difficult, but it takes time. Time that I haven't invested yet.
+Every statement a Connection runs can be logged by passing a Logger (a log.Logger will
+do) to SetLogger. LOG_QUERY loggers get the statements that succeeded, LOG_ERROR loggers
+get the statements that failed, and LOG_ALL loggers get both. Each statement is logged
+as a *LogEntry, which has the SQL, the values, how long it took, the number of rows
+affected and whether the prepared statement came from the QueryCache.
+
+  conn.SetLogger(log.New(os.Stdout, "sql: ", log.LstdFlags), db.LOG_ALL)
+  conn.SetLogger(errorLog, db.LOG_ERROR)
+
+Hooks
+
+Hooks run around each statement a Mapper or Scope sends to the database. Before hooks
+see the Mapper, the Operation, the terminal function, the SQL and the values, and can
+change the SQL or values, or return an error to stop the statement. After hooks see the
+same Statement along with the result, error and the time the statement took.
+
+  conn.AddBeforeHook(func(st *db.Statement) error {
+    if st.Operation == db.DELETE && st.Mapper.TableName() == "audits" {
+      return errors.New("audits can't be deleted")
+    }
+    return nil
+  })
 */
 package db
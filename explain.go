@@ -0,0 +1,172 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+/*
+A QueryPlan is the plan the database made for running a Scope's query, as
+returned by Explain or ExplainAnalyze. The nodes are arranged as the database
+arranged them, with the Children of a node being the steps that feed it.
+
+  plan, e := Posts.EqualTo("permalink", "first_post").Explain()
+  fmt.Println(plan)
+  // SEARCH on posts using posts_permalink
+*/
+type QueryPlan struct {
+	Nodes []*PlanNode
+	// Whether the query was run to collect the Actual fields
+	Analyzed bool
+}
+
+// A PlanNode is a single step of a QueryPlan, fields the database didn't
+// report are left as zero values
+type PlanNode struct {
+	// The kind of step, like a sequential scan or index lookup, named as
+	// the database names it
+	Type string
+	// The table that the step reads
+	Table string
+	// The index used by the step
+	Index string
+	// The estimated number of rows the step will return
+	Rows float64
+	// The estimated cost of the step, in the database's units
+	Cost float64
+	// The number of rows the step returned and the time it took, only
+	// set by ExplainAnalyze
+	ActualRows float64
+	ActualTime time.Duration
+	// The description of the step given by the database
+	Detail   string
+	Children []*PlanNode
+}
+
+// UsesIndex returns whether any step of the plan uses the named index
+func (qp *QueryPlan) UsesIndex(index string) bool {
+	found := false
+	qp.walk(func(pn *PlanNode, depth int) {
+		if strings.EqualFold(pn.Index, index) {
+			found = true
+		}
+	})
+	return found
+}
+
+// Tables returns the tables read by the plan, in the order they're read
+func (qp *QueryPlan) Tables() []string {
+	var tables []string
+	qp.walk(func(pn *PlanNode, depth int) {
+		if pn.Table != "" {
+			tables = append(tables, pn.Table)
+		}
+	})
+	return tables
+}
+
+// String renders the plan as an indented tree, one step per line
+func (qp *QueryPlan) String() string {
+	lines := []string{}
+	qp.walk(func(pn *PlanNode, depth int) {
+		lines = append(lines, strings.Repeat("  ", depth)+pn.String())
+	})
+	return strings.Join(lines, "\n")
+}
+
+func (qp *QueryPlan) walk(f func(pn *PlanNode, depth int)) {
+	var walk func(nodes []*PlanNode, depth int)
+	walk = func(nodes []*PlanNode, depth int) {
+		for _, pn := range nodes {
+			f(pn, depth)
+			walk(pn.Children, depth+1)
+		}
+	}
+	walk(qp.Nodes, 0)
+}
+
+func (pn *PlanNode) String() string {
+	s := pn.Type
+	if pn.Table != "" {
+		s += " on " + pn.Table
+	}
+	if pn.Index != "" {
+		s += " using " + pn.Index
+	}
+	if pn.Cost != 0 {
+		s += fmt.Sprintf(" (cost=%.2f rows=%.0f)", pn.Cost, pn.Rows)
+	} else if pn.Rows != 0 {
+		s += fmt.Sprintf(" (rows=%.0f)", pn.Rows)
+	}
+	if pn.ActualTime != 0 || pn.ActualRows != 0 {
+		s += fmt.Sprintf(" (actual rows=%.0f time=%v)", pn.ActualRows, pn.ActualTime)
+	}
+	return s
+}
+
+// Explain asks the database for the plan it would use to run the query
+// that RetrieveAll would run for the Scope, without running the query.
+func (q *queryable) Explain() (*QueryPlan, error) {
+	return q.explain(false, "Explain")
+}
+
+// ExplainAnalyze runs the query for the Scope and returns the plan the
+// database used along with the actual rows and time of each step. Not
+// every database supports this.
+func (q *queryable) ExplainAnalyze() (*QueryPlan, error) {
+	return q.explain(true, "ExplainAnalyze")
+}
+
+func (q *queryable) explain(analyze bool, method string) (*QueryPlan, error) {
+	if err := q.readErr(); err != nil {
+		return nil, err
+	}
+	d := q.source.conn.Dialect
+	query, values := d.Query(q)
+	query, err := d.Explain(query, analyze)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.source.runRead(q.runContext(method), q.primary, query, values)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plan, err := d.ParsePlan(rows)
+	if err != nil {
+		return nil, err
+	}
+	plan.Analyzed = analyze
+	return plan, rows.Err()
+}
+
+// scanStrings reads every row into a map of column name to value, NULL
+// values are returned as empty strings
+func scanStrings(rows *sql.Rows) ([]map[string]string, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var output []map[string]string
+	values := make([]sql.NullString, len(columns))
+	items := make([]interface{}, len(columns))
+	for i := range values {
+		items[i] = &values[i]
+	}
+	for rows.Next() {
+		if err = rows.Scan(items...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]string)
+		for i, column := range columns {
+			row[strings.ToLower(column)] = values[i].String
+		}
+		output = append(output, row)
+	}
+	return output, rows.Err()
+}
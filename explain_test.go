@@ -0,0 +1,113 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/acsellers/assert"
+)
+
+func TestExplain(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+
+			plan, e := Posts.EqualTo("id", 1).Explain()
+			test.NoError(e)
+			if plan != nil {
+				test.IsTrue(len(plan.Nodes) > 0)
+				test.AreEqual([]string{"posts"}, plan.Tables())
+				test.IsFalse(plan.Analyzed)
+				test.AreNotEqual("", plan.String())
+			}
+		}
+	})
+}
+
+func TestSqlitePlan(t *testing.T) {
+	Within(t, func(test *Test) {
+		pn := sqlitePlanNode("SEARCH posts USING INDEX posts_permalink (permalink=?)")
+		test.AreEqual("SEARCH", pn.Type)
+		test.AreEqual("posts", pn.Table)
+		test.AreEqual("posts_permalink", pn.Index)
+
+		pn = sqlitePlanNode("SCAN TABLE posts")
+		test.AreEqual("SCAN", pn.Type)
+		test.AreEqual("posts", pn.Table)
+		test.AreEqual("", pn.Index)
+
+		pn = sqlitePlanNode("SEARCH posts USING INTEGER PRIMARY KEY (rowid=?)")
+		test.AreEqual("INTEGER PRIMARY KEY", pn.Index)
+
+		pn = sqlitePlanNode("USE TEMP B-TREE FOR ORDER BY")
+		test.AreEqual("USE TEMP B-TREE FOR ORDER BY", pn.Type)
+
+		_, e := newSqlite().Explain("SELECT 1", true)
+		test.IsNotNil(e)
+	})
+}
+
+func TestPostgresPlan(t *testing.T) {
+	Within(t, func(test *Test) {
+		plan, e := parsePostgresPlan([]byte(`[{"Plan": {
+			"Node Type": "Nested Loop", "Total Cost": 16.5, "Plan Rows": 2,
+			"Actual Rows": 1, "Actual Total Time": 0.5,
+			"Plans": [
+				{"Node Type": "Seq Scan", "Relation Name": "posts", "Total Cost": 1.02, "Plan Rows": 2},
+				{"Node Type": "Index Scan", "Relation Name": "users", "Index Name": "users_pkey", "Total Cost": 8.17, "Plan Rows": 1}
+			]
+		}}]`))
+		test.NoError(e)
+		test.AreEqual(1, len(plan.Nodes))
+		test.AreEqual([]string{"posts", "users"}, plan.Tables())
+		test.IsTrue(plan.UsesIndex("users_pkey"))
+		test.AreEqual(500*time.Microsecond, plan.Nodes[0].ActualTime)
+		test.AreEqual(
+			"Nested Loop (cost=16.50 rows=2) (actual rows=1 time=500µs)\n"+
+				"  Seq Scan on posts (cost=1.02 rows=2)\n"+
+				"  Index Scan on users using users_pkey (cost=8.17 rows=1)",
+			plan.String(),
+		)
+	})
+}
+
+func TestMysqlPlan(t *testing.T) {
+	Within(t, func(test *Test) {
+		plan := parseMysqlTree("-> Nested loop inner join  (cost=1.10 rows=2) (actual time=0.05..0.07 rows=2 loops=1)\n" +
+			"    -> Table scan on posts  (cost=0.45 rows=2) (actual time=0.03..0.04 rows=2 loops=1)\n" +
+			"    -> Single-row index lookup on users using PRIMARY (id=posts.user_id)  (cost=0.28 rows=1) (actual time=0.01..0.01 rows=1 loops=2)\n")
+		test.AreEqual(1, len(plan.Nodes))
+		test.AreEqual("Nested loop inner join", plan.Nodes[0].Type)
+		test.AreEqual(2, len(plan.Nodes[0].Children))
+		test.AreEqual([]string{"posts", "users"}, plan.Tables())
+		test.IsTrue(plan.UsesIndex("PRIMARY"))
+		test.AreEqual("Single-row index lookup", plan.Nodes[0].Children[1].Type)
+		test.AreEqual(1.1, plan.Nodes[0].Cost)
+		test.AreEqual(70*time.Microsecond, plan.Nodes[0].ActualTime)
+
+		query, e := newMysql().Explain("SELECT 1", false)
+		test.NoError(e)
+		test.AreEqual("EXPLAIN FORMAT=JSON SELECT 1", query)
+		plan, e = parseMysqlJSON(`{"query_block": {
+			"select_id": 1, "cost_info": {"query_cost": "1.10"},
+			"ordering_operation": {"using_filesort": true, "nested_loop": [
+				{"table": {"table_name": "posts", "access_type": "ALL", "rows_examined_per_scan": 2,
+					"cost_info": {"read_cost": "0.25", "eval_cost": "0.20", "prefix_cost": "0.45"}}},
+				{"table": {"table_name": "users", "access_type": "eq_ref", "key": "PRIMARY", "rows_examined_per_scan": 1,
+					"cost_info": {"read_cost": "0.25", "eval_cost": "0.10", "prefix_cost": "1.10"}}}
+			]}
+		}}`)
+		test.NoError(e)
+		test.AreEqual(1, len(plan.Nodes))
+		test.AreEqual(1.1, plan.Nodes[0].Cost)
+		test.AreEqual([]string{"posts", "users"}, plan.Tables())
+		test.IsTrue(plan.UsesIndex("PRIMARY"))
+		test.AreEqual(
+			"query_block (cost=1.10 rows=0)\n"+
+				"  ordering_operation\n"+
+				"    ALL on posts (cost=0.45 rows=2)\n"+
+				"    eq_ref on users using PRIMARY (cost=1.10 rows=1)",
+			plan.String(),
+		)
+	})
+}
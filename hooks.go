@@ -0,0 +1,144 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// The Operation of a Statement
+type Operation int
+
+// Operations for Statements
+const (
+	SELECT Operation = iota
+	INSERT
+	UPDATE
+	DELETE
+)
+
+func (op Operation) String() string {
+	switch op {
+	case INSERT:
+		return "INSERT"
+	case UPDATE:
+		return "UPDATE"
+	case DELETE:
+		return "DELETE"
+	default:
+		return "SELECT"
+	}
+}
+
+/*
+A Statement is passed to the hooks of a Connection for each statement that a
+Mapper or Scope sends to the database. Statements run directly through the
+Connection's Query, QueryRow and Exec functions don't go through the hooks.
+*/
+type Statement struct {
+	// The context the statement will be run with
+	Context context.Context
+	// The Mapper that is running the statement
+	Mapper Mapper
+	// Whether the statement is a SELECT, INSERT, UPDATE or DELETE
+	Operation Operation
+	// The terminal function that is running the statement, like RetrieveAll,
+	// Count or SaveAll
+	Method string
+	// The SQL, as formatted by the Dialect
+	Query string
+	// The values to bind to the SQL
+	Args []interface{}
+}
+
+// A BeforeHook is run before a statement is sent to the database. It may
+// change the Query or Args of the Statement, returning an error will stop
+// the statement from being run and the error will be returned instead.
+type BeforeHook func(st *Statement) error
+
+// An AfterHook is run after a statement has been run with the error it
+// returned and the time it took. The result is nil for SELECT statements.
+type AfterHook func(st *Statement, result sql.Result, err error, duration time.Duration)
+
+/*
+AddBeforeHook adds a hook that will be run before each statement in the order
+the hooks were added. Transactions started from the Connection will use the
+hooks added before Begin was called.
+
+  // keep every query for Users in the current tenant
+  conn.AddBeforeHook(func(st *db.Statement) error {
+    if st.Mapper.TableName() == "users" && !strings.Contains(st.Query, "tenant_id") {
+      return errors.New("users must be scoped by tenant_id")
+    }
+    return nil
+  })
+*/
+func (c *Connection) AddBeforeHook(hook BeforeHook) {
+	c.beforeHooks = append(c.beforeHooks, hook)
+}
+
+/*
+AddAfterHook adds a hook that will be run after each statement has been run.
+
+  conn.AddAfterHook(func(st *db.Statement, result sql.Result, err error, d time.Duration) {
+    metrics.Timing(st.Mapper.TableName()+"."+st.Method, d)
+  })
+*/
+func (c *Connection) AddAfterHook(hook AfterHook) {
+	c.afterHooks = append(c.afterHooks, hook)
+}
+
+// before creates the Statement for a query and runs the before hooks on it
+func (s *source) before(ctx context.Context, query string, values []interface{}) (*Statement, error) {
+	st := &Statement{
+		Context:   ctx,
+		Mapper:    s,
+		Operation: operationOf(query),
+		Query:     query,
+		Args:      values,
+	}
+	if o := originOf(ctx); o != nil {
+		st.Method = o.method
+	}
+
+	if err := s.detectNPlusOne(st); err != nil {
+		return st, err
+	}
+	for _, hook := range s.conn.beforeHooks {
+		if err := hook(st); err != nil {
+			return st, err
+		}
+	}
+	return st, nil
+}
+
+// after runs the after hooks for a statement started at start
+func (s *source) after(st *Statement, result sql.Result, err error, start time.Time) {
+	if len(s.conn.afterHooks) == 0 {
+		return
+	}
+
+	d := time.Since(start)
+	for _, hook := range s.conn.afterHooks {
+		hook(st, result, err, d)
+	}
+}
+
+// operationOf uses the first keyword of a query to find its Operation
+func operationOf(query string) Operation {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return SELECT
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "INSERT":
+		return INSERT
+	case "UPDATE":
+		return UPDATE
+	case "DELETE":
+		return DELETE
+	default:
+		return SELECT
+	}
+}
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/acsellers/assert"
+)
+
+func TestHooks(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+
+			test.Section("After hooks")
+			var seen []Statement
+			c.AddAfterHook(func(st *Statement, result sql.Result, err error, d time.Duration) {
+				seen = append(seen, *st)
+			})
+			_, e := Posts.Count()
+			test.NoError(e)
+			test.NoError(Posts.EqualTo("id", 2).UpdateAttribute("views", 1))
+			test.AreEqual(2, len(seen))
+			if len(seen) == 2 {
+				test.AreEqual("posts", seen[0].Mapper.TableName())
+				test.AreEqual(SELECT, seen[0].Operation)
+				test.AreEqual("Count", seen[0].Method)
+				test.AreEqual(UPDATE, seen[1].Operation)
+				test.AreEqual("UpdateAttribute", seen[1].Method)
+			}
+
+			test.Section("Rewriting")
+			c.AddBeforeHook(func(st *Statement) error {
+				if st.Operation == SELECT && strings.Contains(st.Query, "WHERE") {
+					st.Args = []interface{}{2}
+				}
+				return nil
+			})
+			var p post
+			test.NoError(Posts.EqualTo("id", 1).Retrieve(&p))
+			test.AreEqual("Second Post", p.Title)
+
+			test.Section("Vetoing")
+			c.beforeHooks = nil
+			c.AddBeforeHook(func(st *Statement) error {
+				if st.Operation == DELETE {
+					return errors.New("no deleting")
+				}
+				return nil
+			})
+			test.AreEqual("no deleting", Posts.EqualTo("id", 1).Delete().Error())
+			ct, e := Posts.Count()
+			test.NoError(e)
+			test.AreEqual(2, ct)
+
+			c.beforeHooks, c.afterHooks = nil, nil
+		}
+	})
+}
@@ -0,0 +1,367 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// An includeHop is a relation whose records are selected along with the
+// records of the Scope and placed in the relation's field. The parent is
+// the index of the hop holding the field, or -1 for the Scope's records.
+type includeHop struct {
+	relation *sourceMapping
+	join     *join
+	parent   int
+	// unmatched is the pointer to the slice for the records of a full or
+	// right include that have no record to be placed into
+	unmatched reflect.Value
+}
+
+func (q *queryable) LeftInclude(include ...interface{}) Scope {
+	return q.includeWith("LEFT", include)
+}
+
+func (q *queryable) InnerInclude(include ...interface{}) Scope {
+	return q.includeWith("INNER", include)
+}
+
+func (q *queryable) FullInclude(include interface{}, nullRecords interface{}) Scope {
+	return q.outerInclude("FULL OUTER", include, nullRecords)
+}
+
+func (q *queryable) RightInclude(include interface{}, nullRecords interface{}) Scope {
+	return q.outerInclude("RIGHT OUTER", include, nullRecords)
+}
+
+// outerInclude includes the descriptor, the included records that don't
+// have a record of the Mapper are placed in nullRecords when retrieved
+func (q *queryable) outerInclude(Type string, include, nullRecords interface{}) Scope {
+	nq := q.Identity().(*queryable)
+	rt := reflect.TypeOf(nullRecords)
+	if rt == nil || rt.Kind() != reflect.Ptr || rt.Elem().Kind() != reflect.Slice {
+		nq.err = errors.New("Must Supply Ptr to Slice")
+		return nq
+	}
+	joins, last, err := nq.addIncludes(Type, include)
+	if err != nil {
+		nq.err = err
+		return nq
+	}
+	nq.addJoins(joins)
+	hop := *nq.includes[last]
+	hop.unmatched = reflect.ValueOf(nullRecords)
+	nq.includes[last] = &hop
+	return nq
+}
+
+// IncludeSql includes the relations in the IncludeList using the join
+// written in query, the tables must be joined under the name that
+// InnerInclude would use for them
+func (q *queryable) IncludeSql(il IncludeList, query string, args ...interface{}) Scope {
+	nq := q.JoinSql(query, args...).(*queryable)
+	for _, desc := range il {
+		if _, _, err := nq.addIncludes("", desc); err != nil {
+			nq.err = err
+			return nq
+		}
+	}
+	return nq
+}
+
+// includeWith joins and includes each descriptor, relations between the
+// Scope's Mapper and the descriptor are included as well
+func (q *queryable) includeWith(Type string, includes []interface{}) Scope {
+	nq := q.Identity().(*queryable)
+	for _, desc := range includes {
+		joins, _, err := nq.addIncludes(Type, desc)
+		if err != nil {
+			nq.err = err
+			return nq
+		}
+		nq.addJoins(joins)
+	}
+	return nq
+}
+
+// addIncludes adds a hop for each relation on the path to the descriptor
+// and returns the joins for the hops that weren't already included, along
+// with the index of the hop for the descriptor
+func (q *queryable) addIncludes(Type string, desc interface{}) ([]*join, int, error) {
+	from, table, path, sq, err := locateJoin(desc, q)
+	if err != nil {
+		return nil, 0, err
+	}
+	parent := -1
+	if from != q.source || table != q.tableAlias() {
+		parent = q.includedAs(table)
+		if parent < 0 {
+			return nil, 0, fmt.Errorf("Can't include %s.%s since %s isn't included", from.Name, path[0].structOptions.Name, table)
+		}
+	}
+	joins, err := joinPath(Type, q, from, table, path)
+	if err != nil {
+		return nil, 0, err
+	}
+	joins, conditions, err := q.scopeJoins(Type, joins, sq)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var added []*join
+	for i, sm := range path {
+		found := -1
+		for hi, hop := range q.includes {
+			if hop.parent == parent && hop.relation == sm {
+				found = hi
+			}
+		}
+		if found < 0 {
+			q.includes = append(q.includes, &includeHop{relation: sm, join: joins[i], parent: parent})
+			found = len(q.includes) - 1
+			added = append(added, joins[i])
+		}
+		parent = found
+	}
+	q.conditions = append(q.conditions, conditions...)
+	// the joins of a joined Scope aren't included
+	return append(added, joins[len(path):]...), parent, nil
+}
+
+// addIncludeHops adds the hops from another Scope that aren't already
+// included, moving their parents to the hops of this Scope
+func (q *queryable) addIncludeHops(hops []*includeHop) {
+	moved := make([]int, len(hops))
+	for i, hop := range hops {
+		parent := hop.parent
+		if parent >= 0 {
+			parent = moved[parent]
+		}
+		moved[i] = -1
+		for qi, existing := range q.includes {
+			if existing.parent == parent && existing.relation == hop.relation {
+				moved[i] = qi
+			}
+		}
+		if moved[i] < 0 {
+			q.includes = append(q.includes, &includeHop{relation: hop.relation, join: hop.join, parent: parent, unmatched: hop.unmatched})
+			moved[i] = len(q.includes) - 1
+		}
+	}
+}
+
+// including is whether the records are retrieved with their includes,
+// custom selections only use the included tables as joins
+func (q *queryable) including() bool {
+	return len(q.includes) > 0 && len(q.selection) == 0
+}
+
+// retrieveOne is Retrieve for Scopes with includes, the rows of the first
+// record are read so that its has many relations are complete. The first
+// record is found with a subquery unless the Scope has full or right
+// includes, since their unmatched records don't have a first record.
+func (q *queryable) retrieveOne(val interface{}) error {
+	qq := q
+	if !q.outerIncluded() {
+		column := q.tableAlias() + "." + q.source.ID.SqlColumn
+		first := q.Identity().(*queryable)
+		first.selection = []selector{selector{Formula: column}}
+		first.with, first.lock, first.preloads = nil, nil, nil
+		first.limit = 1
+		qq = q.Identity().(*queryable)
+		qq.limit, qq.offset = 0, 0
+		qq = qq.EqualTo(column, first).(*queryable)
+	}
+	query, values := qq.source.conn.Dialect.Query(qq)
+	rows, err := qq.source.runRead(qq.runContext("Retrieve"), qq.primary, query, values)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	value := reflect.ValueOf(val).Elem()
+	included, err := qq.retrieveIncluded(rows, value.Type())
+	if err != nil {
+		return err
+	}
+	if len(included) == 0 {
+		return sql.ErrNoRows
+	}
+	value.Set(included[0].Elem())
+	return q.loadPreloads([]reflect.Value{value})
+}
+
+// outerIncluded is whether some of the rows may have no record for the
+// Mapper because of a full or right include
+func (q *queryable) outerIncluded() bool {
+	for _, hop := range q.includes {
+		if hop.unmatched.IsValid() {
+			return true
+		}
+	}
+	return false
+}
+
+// includedAs finds the hop for the joined table name
+func (q *queryable) includedAs(table string) int {
+	for i, hop := range q.includes {
+		if hop.join.tableAlias() == table {
+			return i
+		}
+	}
+	return -1
+}
+
+// includeColumns are the columns of the included tables, named with the
+// name of the joined table and the column
+func (q *queryable) includeColumns() []string {
+	var columns []string
+	for _, hop := range q.includes {
+		alias := hop.join.tableAlias()
+		for _, column := range hop.relation.Relation.selectColumns() {
+			name := strings.TrimPrefix(column, hop.relation.Relation.SqlName+".")
+			columns = append(columns, alias+"."+name+" AS "+alias+"__"+name)
+		}
+	}
+	return columns
+}
+
+// An includeNode is a retrieved record and the included records that are
+// placed into its relation fields, children and seen are by hop index
+type includeNode struct {
+	value    reflect.Value
+	children [][]*includeNode
+	seen     map[string]*includeNode
+}
+
+func newIncludeNode(value reflect.Value, hops int) *includeNode {
+	return &includeNode{
+		value:    value,
+		children: make([][]*includeNode, hops),
+		seen:     make(map[string]*includeNode),
+	}
+}
+
+// retrieveIncluded scans each row into the struct of the Scope's Mapper
+// and the structs of the included relations. Records that are returned in
+// more than one row because of a has many relation are only added once.
+// The returned values are pointers to the Scope's records, the unmatched
+// records of full and right includes are placed in their slices.
+func (q *queryable) retrieveIncluded(rows *sql.Rows, element reflect.Type) ([]reflect.Value, error) {
+	var roots []*includeNode
+	seen := make(map[string]*includeNode)
+	unmatched := newIncludeNode(reflect.Value{}, len(q.includes))
+	outer := q.outerIncluded()
+	for rows.Next() {
+		items := []reflect.Value{reflect.New(element)}
+		plans := []*planner{q.source.mapPlan(reflector{items[0]})}
+		if outer {
+			plans[0] = q.source.includePlan(reflector{items[0]})
+		}
+		scans := plans[0].Items()
+		for _, hop := range q.includes {
+			item := reflect.New(structOf(items[hop.parent+1].Elem().Field(hop.relation.Index).Type()))
+			plan := hop.relation.Relation.includePlan(reflector{item})
+			items, plans = append(items, item), append(plans, plan)
+			scans = append(scans, plan.Items()...)
+		}
+		if err := rows.Scan(scans...); err != nil {
+			return nil, err
+		}
+
+		var root *includeNode
+		if !outer || plans[0].scanned(q.source.ID) {
+			q.Initialize(items[0].Interface())
+			plans[0].Finalize(items[0].Interface())
+			key := fmt.Sprint(q.source.extractID(items[0].Elem()))
+			var ok bool
+			if root, ok = seen[key]; !ok {
+				root = newIncludeNode(items[0], len(q.includes))
+				seen[key] = root
+				roots = append(roots, root)
+			}
+		}
+
+		nodes := make([]*includeNode, len(q.includes))
+		for i, hop := range q.includes {
+			parent := root
+			if hop.parent >= 0 {
+				parent = nodes[hop.parent]
+			}
+			related := hop.relation.Relation
+			if parent == nil && hop.unmatched.IsValid() {
+				parent = unmatched
+			}
+			if parent == nil || !plans[i+1].scanned(related.ID) {
+				continue
+			}
+			item := items[i+1]
+			related.Initialize(item.Interface())
+			plans[i+1].Finalize(item.Interface())
+			key := fmt.Sprint(i, ":", related.extractID(item.Elem()))
+			node, ok := parent.seen[key]
+			if !ok {
+				node = newIncludeNode(item, len(q.includes))
+				parent.seen[key] = node
+				parent.children[i] = append(parent.children[i], node)
+			}
+			nodes[i] = node
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	values := make([]reflect.Value, len(roots))
+	for i, root := range roots {
+		root.place(q.includes)
+		values[i] = root.value
+	}
+	for i, hop := range q.includes {
+		if hop.unmatched.IsValid() {
+			dest := hop.unmatched.Elem()
+			dest.Set(reflect.MakeSlice(dest.Type(), 0, len(unmatched.children[i])))
+			for _, node := range unmatched.children[i] {
+				node.place(q.includes)
+				if dest.Type().Elem().Kind() == reflect.Ptr {
+					dest.Set(reflect.Append(dest, node.value))
+				} else {
+					dest.Set(reflect.Append(dest, node.value.Elem()))
+				}
+			}
+		}
+	}
+	return values, nil
+}
+
+// place sets the relation fields of the record to the included records,
+// the included records have their own fields set first
+func (n *includeNode) place(hops []*includeHop) {
+	for i, children := range n.children {
+		if len(children) == 0 {
+			continue
+		}
+		field := n.value.Elem().Field(hops[i].relation.Index)
+		for _, child := range children {
+			child.place(hops)
+		}
+		switch field.Kind() {
+		case reflect.Slice:
+			items := reflect.MakeSlice(field.Type(), 0, len(children))
+			for _, child := range children {
+				if field.Type().Elem().Kind() == reflect.Ptr {
+					items = reflect.Append(items, child.value)
+				} else {
+					items = reflect.Append(items, child.value.Elem())
+				}
+			}
+			field.Set(items)
+		case reflect.Ptr:
+			field.Set(children[0].value)
+		default:
+			field.Set(children[0].value.Elem())
+		}
+	}
+}
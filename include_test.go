@@ -0,0 +1,128 @@
+package db
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/acsellers/assert"
+)
+
+func TestIncludes(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+			Users := c.m("User")
+
+			test.Section("Selection")
+			sql, _ := Posts.LeftInclude(Users).SelectorSql()
+			test.IsTrue(strings.HasSuffix(sql, ", users.id AS users__id, users.name AS users__name, users.email AS users__email, users.password AS users__password, users.story AS users__story"))
+
+			test.Section("Belongs to")
+			var posts []post
+			test.NoError(Posts.Order("posts.id").LeftInclude("User").RetrieveAll(&posts))
+			test.AreEqual(2, len(posts))
+			if len(posts) == 2 {
+				test.AreEqual("wat", posts[0].User.Name)
+				test.AreEqual(0, posts[1].User.Id)
+			}
+			posts = nil
+			test.NoError(Posts.InnerInclude(Users).RetrieveAll(&posts))
+			test.AreEqual(1, len(posts))
+
+			test.Section("Has many")
+			test.NoError(Posts.EqualTo("id", 2).UpdateAttribute("user_id", 1))
+			var users []user
+			test.NoError(Users.LeftInclude(Posts).Order("posts.id").RetrieveAll(&users))
+			test.AreEqual(1, len(users))
+			if len(users) == 1 {
+				test.AreEqual(2, len(users[0].Post))
+				if len(users[0].Post) == 2 {
+					test.AreEqual("First Post", users[0].Post[0].Title)
+					test.AreEqual("Second Post", users[0].Post[1].Title)
+				}
+			}
+			var u user
+			test.NoError(Users.InnerInclude("Post").Retrieve(&u))
+			test.AreEqual(2, len(u.Post))
+			ct, e := Users.LeftInclude(Posts).Count()
+			test.NoError(e)
+			test.AreEqual(1, ct)
+			ct, e = Users.InnerJoin(Posts).Count()
+			test.NoError(e)
+			test.AreEqual(1, ct)
+			var p post
+			test.NoError(Posts.OrderBy("posts.id", "DESC").LeftInclude("User").Retrieve(&p))
+			test.AreEqual("Second Post", p.Title)
+			test.AreEqual("wat", p.User.Name)
+
+			test.Section("Nested")
+			users = nil
+			test.NoError(Users.LeftInclude(Posts).LeftInclude("User").RetrieveAll(&users))
+			if len(users) == 1 && len(users[0].Post) > 0 {
+				test.AreEqual("wat", users[0].Post[0].User.Name)
+			}
+			test.NoError(Posts.EqualTo("id", 2).UpdateAttribute("user_id", nil))
+
+			test.Section("Sql")
+			posts = nil
+			test.NoError(Posts.IncludeSql(
+				IncludeList{"User"},
+				"INNER JOIN users ON users.id = posts.user_id AND users.name = ?", "wat",
+			).RetrieveAll(&posts))
+			test.AreEqual(1, len(posts))
+			if len(posts) == 1 {
+				test.AreEqual("wat", posts[0].User.Name)
+			}
+		}
+	})
+}
+
+func TestOuterIncludes(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+			Users := c.m("User")
+
+			test.Section("Full includes")
+			var users []user
+			var orphans []post
+			scope := Users.FullInclude(Posts, &orphans)
+			test.NoError(scope.RetrieveAll(&users))
+			test.AreEqual(1, len(users))
+			if len(users) == 1 {
+				test.AreEqual(1, len(users[0].Post))
+			}
+			test.AreEqual(1, len(orphans))
+			if len(orphans) == 1 {
+				test.AreEqual("Second Post", orphans[0].Title)
+			}
+
+			test.Section("Right includes")
+			var posts []post
+			var lonely []*user
+			test.NoError(Posts.RightInclude(Users, &lonely).RetrieveAll(&posts))
+			test.AreEqual(1, len(posts))
+			test.AreEqual(0, len(lonely))
+			test.IsNotNil(Posts.RightInclude(Users, lonely).RetrieveAll(&posts))
+
+			test.Section("Emulated full joins")
+			q := scope.(*queryable).fullJoinUnion()
+			query, values := selectSql(q)
+			test.IsTrue(strings.Contains(query, " LEFT JOIN posts ON "))
+			test.IsTrue(strings.Contains(query, " UNION ALL "))
+			test.IsTrue(strings.Contains(query, " RIGHT OUTER JOIN posts ON "))
+			test.IsTrue(strings.Contains(query, "users.id IS NULL"))
+			rows, e := c.Query(c.Dialect.FormatQuery(query), values...)
+			test.NoError(e)
+			if e == nil {
+				orphans = nil
+				included, e := scope.(*queryable).retrieveIncluded(rows, reflect.TypeOf(user{}))
+				rows.Close()
+				test.NoError(e)
+				test.AreEqual(1, len(included))
+				test.AreEqual(1, len(orphans))
+			}
+		}
+	})
+}
@@ -1,5 +1,9 @@
 package db
 
+import (
+	"context"
+)
+
 /*
 The Identity Scope will always return a copy of the current scope, whether it
 is on a Mapper, Scope or Mapper+. Internally it is the canonical method to
@@ -71,6 +75,22 @@ within a SQL statement.
     },
   )
 
+The Or Scope combines the conditions of Scopes for the same Mapper, records that
+match the conditions of the Scope or the conditions of any of the Scopes passed are
+matched. Joins from any of the Scopes are kept. The Not Scope matches records that
+don't match the conditions of the Scope passed.
+
+  // find popular posts or posts by the admin
+  popular := Posts.Cond("views", db.GTE, 1000)
+  Posts.EqualTo("user_id", adminId).Or(popular)
+  // WHERE ((user_id = ?) OR (views >= ?))
+
+  // find users that aren't admins or banned
+  Users.Not(Users.Or(admins, banned))
+
+A Scope without conditions matches every record, so it matches every record when
+combined with Or as well. Or on a Mapper only combines the Scopes passed to it.
+
 The Limit Scope allows you to specify the maximum number of records returned in a
 RetrieveAll call.
 
@@ -112,6 +132,16 @@ send along an array to put the records that can't be joined. Read up on how
 Right and Full Outer Joins work if you don't understand why you have to send
 in the array.
 
+The columns of included tables are selected as table__column after the columns of
+the Mapper. A record that is returned in several rows because of an included has
+many relation is only placed in the results once, with every related record in its
+slice field. Limits apply to the joined rows, so use Preload for has many relations
+of a limited Scope. Retrieve only reads the rows of the first record, and Count
+counts each record of a Scope with joins once.
+
+  // Users with their Posts and the User of each Post, from one query
+  Users.LeftInclude(Posts).LeftInclude("User").RetrieveAll(&users)
+
 Specifying Joins or Includes without writing SQL is done in several ways. The
 simplest is to just pass in the Mapper or MapperPlus for the table you wish
 to join or include. That works fine for then the join is a simple unaliased
@@ -126,6 +156,14 @@ an Attendee Join Mapper, you could use the string "Coordinator" to indicate
 that Users join to work as opposed to the Attendee Users that would happen
 if you just passed in the Users Mapper.
 
+A Scope for the joined Mapper can be passed as well. The conditions of a Scope
+passed to InnerJoin are added to the WHERE clause, while the conditions of a Scope
+passed to the outer joins are added to the ON clause of the join so that the rows
+without a match are still returned. Joins of the passed Scope are added after it.
+
+  // every post, with the author when the author is an admin
+  Posts.LeftJoin(Users.EqualTo("users.admin", true))
+
 Simple Join Examples
 
   // Join Comments to Users for a recentPost, then select some things
@@ -149,10 +187,155 @@ SQL Join Examples
     calendared.parent_id = meeting.id AND calendared.parent_type = 'Meeting'
   `)
 
+Preloading Relations
+
+Including a has many relation repeats the columns of a record for each related
+record. Preload instead retrieves the records first, then runs one query per relation
+with an IN condition on the keys of the retrieved records, and places the related
+records in the relation fields. Nested relations are named with a path, and passing
+a Scope after a relation name uses that Scope to retrieve the relation. Large sets of
+keys are split into batches of the Connection's PreloadBatchSize.
+
+  // Users with their published Posts, and the Comments on those Posts
+  Users.Preload("Posts", Posts.EqualTo("published", true), "Posts.Comments").RetrieveAll(&users)
+
+Many to Many Relations
+
+When two structs hold slices of each other, like Tags []Tag on Post and Posts []Post
+on Tag, the relation is has and belongs to many through a join table. The join table
+is named by the Config's JoinTableName, posts_tags for the Rails config, or by a
+db_join_table tag on either field. Joins, includes and preloads go through the join
+table, and the Associate and Dissociate methods of the Mixin add and remove its rows.
+The key columns are named with the Config's ForeignKeyName, or by db_join_key and
+db_join_foreign_key tags. A relation from a struct to itself needs the key tags,
+since the two keys would otherwise have the same name.
+
+  type Post struct {
+    Id   int
+    Tags []Tag `db_join_table:"post_tags"`
+    *db.Mixin
+  }
+
+  post.Associate("Tags", &golang, &databases)
+  Posts.InnerJoin(Tags).EqualTo("tags.name", "golang").RetrieveAll(&posts)
+  post.Dissociate("Tags", &databases)
+
+  type User struct {
+    Id      int
+    Friends []User `db_join_table:"friendships" db_join_foreign_key:"friend_id"`
+    *db.Mixin
+  }
+
+Subqueries
+
+Scopes can be used as values for In, Cond, EqualTo and Where, their SQL will be
+placed in the query and their values bound along with the other values. Use
+Columns to pick the column the subquery returns. The From Scope uses a Scope as a
+derived table in place of the Mapper's table.
+
+  // find users with a popular post
+  Users.In("id", Posts.Cond("views", db.GT, 100).Columns("user_id"))
+
+  // find users that have commented
+  Users.Where("EXISTS (?)", Comments.Where("comments.user_id = users.id"))
+
+  // find posts with more views than the average
+  Posts.Cond("views", db.GT, Posts.Columns(db.Func("AVG(views)")))
+
+  // find the posts in the top 10 by views that are by a user
+  Posts.From(Posts.Order("views DESC").Limit(10), "popular").EqualTo("user_id", userId)
+
+Window Functions
+
+A SqlFunc can be made into a window function with Over, giving a Window. Windows
+can be selected with Columns, where the value is put into the struct field for
+the Window's alias, used in Order, or filtered on by selecting from a Scope with
+From.
+
+  // the latest post of each user
+  rn := db.Func("ROW_NUMBER()").Over("user_id", "created_at DESC").As("rn")
+  Posts.From(Posts.Columns("*", rn), "ranked").EqualTo("rn", 1)
+
+Common Table Expressions
+
+The With Scope adds a common table expression to the query, which can be used as
+a table by the rest of the Scope. WithRecursive takes an anchor Scope and a
+recursive Scope that refers to the common table, for walking trees of records.
+Recursive common tables work with postgres, sqlite3 and MySQL 8.
+
+  // find an employee and everyone that reports up to them
+  Employees.WithRecursive(
+    "reports",
+    Employees.EqualTo("id", managerId),
+    Employees.JoinSql("INNER JOIN reports ON employees.manager_id = reports.id"),
+  ).From("reports", "")
+
+Set Operations
+
+The Union, UnionAll, Intersect and Except Scopes combine the records of two Scopes
+that select the same columns. The result is a Scope, so conditions, orderings and
+limits can be added for the combined records, which are retrieved into the struct
+of the first Scope's Mapper. MySQL doesn't support Intersect or Except, the Scope
+will return an error instead.
+
+  // posts that are popular or recent, but not by banned users
+  Posts.Cond("views", db.GT, 1000).
+    Union(Posts.Cond("created_at", db.GT, lastWeek)).
+    Except(Posts.In("user_id", bannedIds)).
+    Order("views DESC")
+
+Combining Scopes
+
+Scopes built separately can be combined with Merge. The conditions of both Scopes
+are used, joins are only added once, and the orderings of the passed Scope come
+after the orderings of the Scope it is merged into. The limit, offset, group by
+and having of the passed Scope win when they're set. Merging Scopes for different
+Mappers returns an error.
+
+  published := Posts.EqualTo("published", true)
+  popular := Posts.Order("views DESC").Limit(10)
+  scope, err := published.Merge(popular)
+  // WHERE published = ? ORDER BY views DESC LIMIT 10
+
+Cancellation
+
+The WithContext Scope sets the context.Context that the terminal functions (Find,
+Retrieve, RetrieveAll, Count, Pluck, Delete and the Update* functions) will run their
+queries with. Cancelling the context or passing its deadline will stop the query.
+
+  // stop looking for posts if the request goes away
+  Posts.WithContext(req.Context()).EqualTo("user_id", userId).RetrieveAll(&posts)
+
+Replicas
+
+When a Connection has replicas (see AddReplica), the read terminals are run on
+the replicas. The OnPrimary Scope will send the reads of a Scope to the primary
+database, for instance right after you've saved something you need to read back.
+
+  Posts.SaveAll(&post)
+  Posts.OnPrimary().EqualTo("user_id", post.UserId).Count()
+
+Row Locks
+
+Inside of a transaction, the ForUpdate and ForShare Scopes lock the rows that are
+read until the transaction ends. NoWait returns an error instead of waiting for rows
+another transaction has locked, SkipLocked leaves those rows out. Locking Scopes
+return an error when they're used outside of a transaction, or when the database
+can't lock the rows, like postgres with a GROUP BY. Sqlite locks the whole database
+for a transaction, so no locking clause is added on sqlite.
+
+  tx.Mapper(Jobs).EqualTo("state", "queued").Order("id").ForUpdate().SkipLocked().Retrieve(&job)
+
 */
 type Queryable interface {
 	// Identity is the canonical way to duplicate a Scope, it doesn't do anything else
 	Identity() Scope
+	// WithContext sets the context that the queries run by the Scope will use, so
+	// they may be cancelled or given a deadline
+	WithContext(ctx context.Context) Scope
+	// OnPrimary sends the reads for the Scope to the primary database instead
+	// of a replica, for when you need to read what you've just written
+	OnPrimary() Scope
 
 	// Cond is a quick interface to the simple compare operations
 	Cond(column string, condition COND, val interface{}) Scope
@@ -166,6 +349,43 @@ type Queryable interface {
 	// like EqualTo, Cond, Between or In written in SQL. It will also handle binding variables
 	// within a SQL statement.
 	Where(fragment string, args ...interface{}) Scope
+	// Or matches records that match the conditions of the Scope, or the conditions
+	// of any of the scopes passed, which must be Scopes for the same Mapper. Or on a
+	// Mapper matches records that match any of the scopes passed.
+	Or(scopes ...Scope) Scope
+	// Not matches records that don't match the conditions of the scope passed
+	Not(scope Scope) Scope
+	// From selects from a Scope or table SQL instead of the Mapper's table, the
+	// alias is the name the table is given in the query
+	From(table interface{}, alias string) Scope
+	// Columns sets the columns or formulas selected by the Scope, for Scopes
+	// that will be used as subqueries
+	Columns(columns ...interface{}) Scope
+	// With adds a common table expression named name to the query, the name may
+	// then be used as a table in From, JoinSql, Where and subqueries
+	With(name string, cte Scope) Scope
+	// WithRecursive adds a recursive common table expression to the query, the
+	// rows of the recursive Scope are added to the rows of the anchor Scope
+	WithRecursive(name string, anchor, recursive Scope) Scope
+	// Union returns a Scope for the records of either Scope, the Scopes must select
+	// the same columns
+	Union(scope Scope) Scope
+	// UnionAll is a Union that keeps duplicate records
+	UnionAll(scope Scope) Scope
+	// Intersect returns a Scope for the records in both Scopes
+	Intersect(scope Scope) Scope
+	// Except returns a Scope for the records that aren't in the scope passed
+	Except(scope Scope) Scope
+	// ForUpdate locks the rows read by the Scope until the transaction ends
+	ForUpdate() Scope
+	// ForShare locks the rows read by the Scope against updates until the transaction ends
+	ForShare() Scope
+	// NoWait returns an error instead of waiting for rows locked by another transaction
+	NoWait() Scope
+	// SkipLocked leaves out rows that are locked by another transaction
+	SkipLocked() Scope
+	// Merge combines two Scopes for the same Mapper, see Combining Scopes
+	Merge(scope Scope) (Scope, error)
 
 	// The Having SQL clause allows you to filter on aggregated
 	// values from a GROUP BY. Since Having always is using SQL
@@ -182,12 +402,13 @@ type Queryable interface {
 	// Offset sets the number of results to skip over before returning results
 	Offset(offset int) Scope
 
-	// Order sets an ordering column for the query, direction is ASC unless specified
-	Order(ordering string) Scope
+	// Order sets an ordering column, SqlFunc or Window for the query, direction is ASC
+	// unless specified
+	Order(ordering interface{}) Scope
 	// Specify both an ordering and direction as separate parameters
 	OrderBy(column, direction string) Scope
 	// Drop all previous order declarations and only order by the parameter passed
-	Reorder(ordering string) Scope
+	Reorder(ordering interface{}) Scope
 
 	// Search for a record with the primary key of id, then place the result in the val pointer
 	Find(id, val interface{}) error
@@ -195,12 +416,40 @@ type Queryable interface {
 	Retrieve(val interface{}) error
 	// Return all results from the Scope and put the in the array pointed at by the dest parameter
 	RetrieveAll(dest interface{}) error
-	// Return the count results, uses the primary key of the originating mapper to count on
-	// Not distinct, need to add a CountSql function
+	// Return the count results, uses the primary key of the originating mapper to count on.
+	// Grouped, limited or DISTINCT Scopes are counted by wrapping them in a subquery
 	Count() (int64, error)
+	// Exists returns whether any records match the Scope
+	Exists() (bool, error)
+	// CountOn counts a column or expression, like "DISTINCT user_id", instead of the
+	// primary key
+	CountOn(column string) (int64, error)
+	// Sum, Avg, Min and Max place an aggregate of the column into the dest pointer,
+	// dest is set to its zero value when there are no records
+	Sum(column string, dest interface{}) error
+	Avg(column string, dest interface{}) error
+	Min(column string, dest interface{}) error
+	Max(column string, dest interface{}) error
+	// CountBy, SumBy, AvgBy, MinBy and MaxBy fill the map pointed at by dest with an
+	// aggregate for each group of a Scope with a GroupBy, keyed by the group
+	CountBy(dest interface{}) error
+	SumBy(column string, dest interface{}) error
+	AvgBy(column string, dest interface{}) error
+	MinBy(column string, dest interface{}) error
+	MaxBy(column string, dest interface{}) error
 	// Retrieve a single column using joins, limits, conditions from the Scope and place
 	// the results into the array pointed at by values
 	Pluck(column, values interface{}) error
+	// PluckSeveral retrieves multiple columns, placing each into the array pointed at by
+	// the value in the same position
+	PluckSeveral(columns []string, values ...interface{}) error
+	// Select retrieves the columns or formulas in the SQL selection into any struct or
+	// array of structs, matching the columns to the fields by name
+	Select(columns string, dest interface{}) error
+	// Explain returns the plan the database would use to run the query for RetrieveAll
+	Explain() (*QueryPlan, error)
+	// ExplainAnalyze runs the query and returns the plan with the actual rows and times
+	ExplainAnalyze() (*QueryPlan, error)
 
 	// Run a DELETE FROM query using the conditions from the Scope
 	Delete() error
@@ -227,16 +476,20 @@ type Queryable interface {
 	// JoinSql will allow you to write straight SQL for the JOIN
 	JoinSql(sql string, args ...interface{}) Scope
 
-	// LeftInclude
+	// LeftInclude joins like LeftJoin, and places the joined records into the relation fields
 	LeftInclude(include ...interface{}) Scope
-	// InnerInclude
+	// InnerInclude is LeftInclude with inner joins, records without the relation are left out
 	InnerInclude(include ...interface{}) Scope
-	// FullInclude
+	// FullInclude includes with a full outer join, included records without a record
+	// of the Mapper are placed in nullRecords, which must be a pointer to a slice
 	FullInclude(include interface{}, nullRecords interface{}) Scope
-	// RightInclude
+	// RightInclude is FullInclude with a right outer join
 	RightInclude(include interface{}, nullRecords interface{}) Scope
-	// IncludeSql, magic come true
+	// IncludeSql includes the relations in the IncludeList using a join written in SQL
 	IncludeSql(il IncludeList, query string, args ...interface{}) Scope
+	// Preload retrieves the named relations with a separate query for each relation
+	// after the records are retrieved, a Scope after a relation name scopes that relation
+	Preload(relations ...interface{}) Scope
 }
 
 type IncludeList []interface{}
@@ -254,10 +507,13 @@ could be written without explicit support from db, these function (though we'll
 get around to adding more) need to be publicly accessible.
 */
 type ScopeInformation interface {
-	SelectorSql() string
+	WithSql() (string, []interface{})
+	SelectorSql() (string, []interface{})
+	FromSql() (string, []interface{})
 	ConditionSql() (string, []interface{})
-	JoinsSql() string
+	JoinsSql() (string, []interface{})
 	EndingSql() (string, []interface{})
+	LockSql() string
 }
 
 /*
@@ -275,6 +531,7 @@ type Mapper interface {
 	TableInformation
 	Initialize(val ...interface{}) error
 	SaveAll(val interface{}) error
+	SaveAllContext(ctx context.Context, val interface{}) error
 }
 
 // A MapperPlus is both a Scope-like interface, but also the Mapper for a struct.
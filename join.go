@@ -1,6 +1,8 @@
 package db
 
 import (
+	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -12,6 +14,9 @@ type join struct {
 	Matches  []string
 	Args     []interface{}
 	Compiled string
+	// Through is the join to the join table of a has and belongs to many
+	// relation, it comes before this join
+	Through *join
 }
 
 func (j *join) Fragment() string {
@@ -19,6 +24,9 @@ func (j *join) Fragment() string {
 		return j.Compiled
 	} else {
 		output := j.Type + " JOIN " + j.Table
+		if j.Through != nil {
+			output = j.Through.Fragment() + " " + output
+		}
 		if j.Alias != "" {
 			output += " AS " + j.Alias
 		}
@@ -33,31 +41,260 @@ func (j *join) String() string {
 	return withVars(j.Fragment(), j.Values())
 }
 
-func newJoin(Type string, desc interface{}, on *queryable) ([]*join, []condition) {
-	//j := &join{Type: Type}
+// newJoin builds the joins needed to reach the Mapper, Scope or relation
+// name in desc from the Scope, along with any conditions from a joined Scope
+// that belong in the WHERE clause
+func newJoin(Type string, desc interface{}, on *queryable) ([]*join, []condition, error) {
+	from, table, path, sq, err := locateJoin(desc, on)
+	if err != nil {
+		return nil, nil, err
+	}
+	joins, err := joinPath(Type, on, from, table, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return on.scopeJoins(Type, joins, sq)
+}
+
+// locateJoin finds the path of relations to the descriptor of a join, and
+// the source and table name the path starts from. The queryable of a joined
+// Scope is returned as well so its conditions and joins can be added.
+func locateJoin(desc interface{}, on *queryable) (*source, string, []*sourceMapping, *queryable, error) {
+	var target *source
+	var sq *queryable
 	switch dv := desc.(type) {
 	case *source:
-		path := sourceVisitor(on.source, dv)
-		if len(path) > 0 {
-			return []*join{}, []condition{}
-		} else {
-			for _, qj := range on.joins {
-				path = sourceVisitor(qj.Joined, dv)
-				if len(path) > 0 {
-					return []*join{}, []condition{}
-				}
+		target = dv
+	case string:
+		from, table, path := locateAlias(on, dv)
+		if len(path) == 0 {
+			return nil, "", nil, nil, fmt.Errorf("Couldn't find a relation named %s to join to %s", dv, on.source.Name)
+		}
+		return from, table, path, nil, nil
+	case Scope:
+		sq = scopeOf(dv)
+		if sq == nil {
+			return nil, "", nil, nil, fmt.Errorf("Can't join a %T", desc)
+		}
+		if sq.err != nil {
+			return nil, "", nil, nil, sq.err
+		}
+		target = sq.source
+	default:
+		return nil, "", nil, nil, fmt.Errorf("Can't join a %T", desc)
+	}
+
+	from, table, path := locateRelation(on, target)
+	if len(path) == 0 {
+		return nil, "", nil, nil, fmt.Errorf("Couldn't find a relation from %s to %s", on.source.Name, target.Name)
+	}
+	return from, table, path, sq, nil
+}
+
+// scopeJoins adds the conditions and joins of a joined Scope to the joins
+// that reach its table. The conditions of outer joins are matched in the ON
+// clause of the join to the Scope's table so that the rows without a match
+// are kept, the conditions of other joins are returned for the WHERE clause.
+func (q *queryable) scopeJoins(Type string, joins []*join, sq *queryable) ([]*join, []condition, error) {
+	if sq == nil {
+		return joins, nil, nil
+	}
+	last := joins[len(joins)-1]
+	if len(sq.joins) > 0 && last.tableAlias() != sq.tableAlias() {
+		return nil, nil, fmt.Errorf("Can't join the joins of a %s Scope to %s", sq.source.Name, last.tableAlias())
+	}
+
+	var conditions []condition
+	switch {
+	case len(sq.conditions) == 0:
+	case Type == "LEFT" || Type == "RIGHT OUTER" || Type == "FULL OUTER":
+		ac := &andCondition{sq.conditions}
+		matched := *last
+		matched.Matches = append(append([]string(nil), last.Matches...), ac.Fragment())
+		matched.Args = append(append([]interface{}(nil), last.Args...), q.cleanValues(ac.Values())...)
+		joins[len(joins)-1] = &matched
+		// a join that was already in the Scope is replaced by the matched join
+		for i, existing := range q.joins {
+			if existing == last {
+				q.joins[i] = &matched
 			}
 		}
-	case *mapperPlus:
-		//path := sourceVisitor(on.source, dv.source)
-	case *queryable:
-	case string:
+	default:
+		conditions = sq.conditions
+	}
+	return append(joins, sq.joins...), conditions, nil
+}
+
+// locateRelation finds the shortest path of relations to the source,
+// starting from the Scope's table then from each table already joined
+func locateRelation(from *queryable, to *source) (*source, string, []*sourceMapping) {
+	if path := sourceVisitor(from.source, to); len(path) > 0 {
+		return from.source, from.tableAlias(), path
+	}
+	for _, qj := range from.joins {
+		if qj.Joined == nil {
+			continue
+		}
+		if path := sourceVisitor(qj.Joined, to); len(path) > 0 {
+			return qj.Joined, qj.tableAlias(), path
+		}
+	}
+	return nil, "", nil
+}
+
+// locateAlias is locateRelation for relations named by their struct field
+func locateAlias(from *queryable, to string) (*source, string, []*sourceMapping) {
+	if path := aliasVisitor(from.source, to); len(path) > 0 {
+		return from.source, from.tableAlias(), path
+	}
+	for _, qj := range from.joins {
+		if qj.Joined == nil {
+			continue
+		}
+		if path := aliasVisitor(qj.Joined, to); len(path) > 0 {
+			return qj.Joined, qj.tableAlias(), path
+		}
+	}
+	return nil, "", nil
+}
+
+// joinPath turns each relation in the path into a join, aliased relations
+// are joined using the column name of the field as the table alias, and
+// tables that are already in the Scope are aliased by the table joined from
+func joinPath(Type string, on *queryable, from *source, table string, path []*sourceMapping) ([]*join, error) {
+	used := map[string]bool{on.tableAlias(): true}
+	for _, j := range on.joins {
+		if j.Compiled == "" {
+			used[j.tableAlias()] = true
+		}
+	}
+	joins := make([]*join, 0, len(path))
+	for _, sm := range path {
+		if sm.ForeignKey == nil && sm.Through == nil {
+			return nil, fmt.Errorf("Couldn't find a foreign key for %s.%s", from.Name, sm.structOptions.Name)
+		}
+		alias := ""
+		if sm.Aliased() {
+			alias = from.conn.Config.FieldToColumn(from.Name, sm.structOptions.Name)
+		}
+		j := relationJoin(Type, sm, from, table, alias)
+		if used[j.tableAlias()] {
+			// the same join is kept, other joins to the table need an alias
+			if existing := on.joinFor(j); existing != nil {
+				j = existing
+			} else if !sm.Aliased() {
+				alias = table + "_" + from.conn.Config.FieldToColumn(from.Name, sm.structOptions.Name)
+				j = relationJoin(Type, sm, from, table, alias)
+			}
+		}
+		used[j.tableAlias()] = true
+		joins = append(joins, j)
+		from, table = sm.Relation, j.tableAlias()
+	}
+	return joins, nil
+}
+
+// relationJoin is the join for the relation from table to its related
+// table, has and belongs to many relations join through the join table
+func relationJoin(Type string, sm *sourceMapping, from *source, table, alias string) *join {
+	j := &join{Type: Type, Table: sm.Relation.SqlName, Joined: sm.Relation, Alias: alias}
+	joined := j.tableAlias()
+	switch {
+	case sm.Through != nil:
+		through := &join{Type: Type, Table: sm.Through.Table}
+		if alias != "" {
+			through.Alias = alias + "_" + sm.Through.Table
+		}
+		through.Matches = []string{through.tableAlias() + "." + sm.Through.Key + " = " + table + "." + from.ID.SqlColumn}
+		j.Through = through
+		j.Matches = []string{joined + "." + sm.Relation.ID.SqlColumn + " = " + through.tableAlias() + "." + sm.Through.ForeignKey}
+	case sm.Kind != reflect.Slice && from.hasColumn(sm.ForeignKey):
+		// belongs to, the foreign key is in the table we're joining from
+		j.Matches = []string{joined + "." + sm.Relation.ID.SqlColumn + " = " + table + "." + sm.ForeignKey.SqlColumn}
+	default:
+		// has one or has many, the foreign key is in the joined table
+		j.Matches = []string{joined + "." + sm.ForeignKey.SqlColumn + " = " + table + "." + from.ID.SqlColumn}
+	}
+	return j
+}
+
+// joinFor finds a join in the Scope to the same table on the same columns
+func (q *queryable) joinFor(j *join) *join {
+	for _, existing := range q.joins {
+		if existing.Compiled == "" && existing.Table == j.Table && existing.Alias == j.Alias &&
+			strings.Join(existing.Matches, " AND ") == strings.Join(j.Matches, " AND ") {
+			return existing
+		}
+	}
+	return nil
+}
+
+// fullJoined is whether the Scope has a FULL OUTER JOIN
+func (q *queryable) fullJoined() bool {
+	for _, j := range q.joins {
+		if j.Type == "FULL OUTER" {
+			return true
+		}
+	}
+	return false
+}
+
+// fullJoinUnion rewrites the full joins of the Scope for databases without
+// them, as the Scope with left joins UNION ALL the Scope with right joins
+// where the Mapper's table is missing. The combined rows are selected from
+// under the Mapper's table name, so the selection, grouping and ordering
+// may only use the Mapper's columns and the table__column names of the
+// included columns.
+func (q *queryable) fullJoinUnion() *queryable {
+	member := q.Identity().(*queryable)
+	member.selection, member.order, member.limit, member.offset = nil, nil, 0, 0
+	member.groupBy, member.having, member.with, member.lock = "", nil, nil, nil
+
+	left, right := member.Identity().(*queryable), member.Identity().(*queryable)
+	for i, j := range member.joins {
+		if j.Type == "FULL OUTER" {
+			lj, rj := *j, *j
+			lj.Type, rj.Type = "LEFT", "RIGHT OUTER"
+			if j.Through != nil {
+				lt, rt := *j.Through, *j.Through
+				lt.Type, rt.Type = "LEFT", "RIGHT OUTER"
+				lj.Through, rj.Through = &lt, &rt
+			}
+			left.joins[i], right.joins[i] = &lj, &rj
+		}
+	}
+	right = right.EqualTo(q.tableAlias()+"."+q.source.ID.SqlColumn, nil).(*queryable)
+
+	selection := q.selection
+	if len(selection) == 0 {
+		selection = []selector{selector{Formula: "*"}}
+	}
+	return &queryable{
+		source:    q.source,
+		ctx:       q.ctx,
+		primary:   q.primary,
+		order:     q.order,
+		groupBy:   q.groupBy,
+		having:    q.having,
+		offset:    q.offset,
+		limit:     q.limit,
+		selection: selection,
+		from:      &fromTable{&compoundTable{"UNION ALL", left, right}, q.tableAlias()},
+		with:      q.with,
+		lock:      q.lock,
+	}
+}
+
+// tableAlias is the name that the joined table can be referred to by
+func (j *join) tableAlias() string {
+	if j.Alias != "" {
+		return j.Alias
 	}
-	return []*join{}, []condition{}
+	return j.Table
 }
 
-func locateRelation(from *queryable, to *source) []*source {
-	panic("NOT HERE")
+func (s *source) hasColumn(ci *ColumnInfo) bool {
+	return s.mappingFor(ci) != nil
 }
 
 type relationRoute struct {
@@ -83,13 +320,14 @@ func sourceVisitor(f, t *source) []*sourceMapping {
 		for _, r := range c.head.Relation.relations {
 			if !visited[r.Relation] {
 				visited[r.Relation] = true
-				queue = append(queue, relationRoute{r, append(c.body, r)})
+				queue = append(queue, relationRoute{r, append(append([]*sourceMapping{}, c.body...), r)})
 			}
 		}
 	}
 	return []*sourceMapping{}
 }
 
+// aliasVisitor is sourceVisitor for a relation field name, aliased or not
 func aliasVisitor(f *source, t string) []*sourceMapping {
 	queue := []relationRoute{}
 	for _, r := range f.relations {
@@ -102,12 +340,12 @@ func aliasVisitor(f *source, t string) []*sourceMapping {
 		c := queue[0]
 		visited[c.head.Relation] = true
 		queue = queue[1:]
-		if c.head.structOptions.Name == t && c.head.Aliased() {
+		if c.head.structOptions.Name == t {
 			return c.body
 		}
 		for _, r := range c.head.Relation.relations {
 			if !visited[r.Relation] {
-				queue = append(queue, relationRoute{r, append(c.body, r)})
+				queue = append(queue, relationRoute{r, append(append([]*sourceMapping{}, c.body...), r)})
 			}
 		}
 	}
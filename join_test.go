@@ -2,6 +2,7 @@ package db
 
 import (
 	. "github.com/acsellers/assert"
+	"reflect"
 	"testing"
 )
 
@@ -28,3 +29,85 @@ func TestAliased(t *testing.T) {
 
 	})
 }
+
+func TestJoins(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+			Users := c.m("User")
+
+			test.Section("Mapper joins")
+			sql, _ := Posts.InnerJoin(Users).JoinsSql()
+			test.AreEqual(" INNER JOIN users ON users.id = posts.user_id", sql)
+			sql, _ = Users.LeftJoin(Posts).JoinsSql()
+			test.AreEqual(" LEFT JOIN posts ON posts.user_id = users.id", sql)
+			ct, e := Posts.InnerJoin(Users).Count()
+			test.NoError(e)
+			test.AreEqual(1, ct)
+
+			test.Section("String joins")
+			sql, _ = Posts.InnerJoin("User").JoinsSql()
+			test.AreEqual(" INNER JOIN users ON users.id = posts.user_id", sql)
+			sql, _ = Posts.InnerJoin("User", Users).JoinsSql()
+			test.AreEqual(" INNER JOIN users ON users.id = posts.user_id", sql)
+			_, e = Posts.InnerJoin("Editor").Count()
+			test.IsNotNil(e)
+
+			test.Section("Scope joins")
+			ct, e = Posts.InnerJoin(Users.EqualTo("users.name", "wat")).Count()
+			test.NoError(e)
+			test.AreEqual(1, ct)
+			ct, e = Posts.InnerJoin(Users.EqualTo("users.name", "nobody")).Count()
+			test.NoError(e)
+			test.AreEqual(0, ct)
+
+			test.Section("Outer Scope joins match in the ON clause")
+			sql, values := Posts.LeftJoin(Users.EqualTo("users.name", "nobody")).JoinsSql()
+			test.AreEqual(" LEFT JOIN users ON users.id = posts.user_id AND (users.name = ?)", sql)
+			test.AreEqual([]interface{}{"nobody"}, values)
+			ct, e = Posts.LeftJoin(Users.EqualTo("users.name", "nobody")).Count()
+			test.NoError(e)
+			test.AreEqual(2, ct)
+			ct, e = Posts.LeftJoin(Users.EqualTo("users.name", "nobody")).EqualTo("users.id", nil).Count()
+			test.NoError(e)
+			test.AreEqual(2, ct)
+		}
+	})
+}
+
+func TestJoinPaths(t *testing.T) {
+	Within(t, func(test *Test) {
+		conn := &Connection{Config: NewRailsConfig()}
+		id := func(table string) *sourceMapping {
+			return &sourceMapping{&structOptions{Name: "Id"}, &ColumnInfo{SqlTable: table, SqlColumn: "id"}}
+		}
+		users := &source{Name: "User", FullName: "db:User", SqlName: "users", ID: id("users"), conn: conn}
+		entries := &source{Name: "Entry", FullName: "db:Entry", SqlName: "entries", ID: id("entries"), conn: conn}
+		threads := &source{Name: "Thread", FullName: "db:Thread", SqlName: "threads", ID: id("threads"), conn: conn}
+
+		userID := &ColumnInfo{SqlTable: "entries", SqlColumn: "user_id"}
+		threadID := &ColumnInfo{SqlTable: "entries", SqlColumn: "thread_id"}
+		openerID := &ColumnInfo{SqlTable: "threads", SqlColumn: "opener_id"}
+		entries.Fields = []*sourceMapping{entries.ID, {&structOptions{Name: "UserId"}, userID}, {&structOptions{Name: "ThreadId"}, threadID}}
+		threads.Fields = []*sourceMapping{threads.ID, {&structOptions{Name: "OpenerId"}, openerID}}
+		entries.relations = []*sourceMapping{
+			{&structOptions{Name: "User", FullName: "db:User", Kind: reflect.Struct, Relation: users, ForeignKey: userID}, nil},
+		}
+		threads.relations = []*sourceMapping{
+			{&structOptions{Name: "Entries", FullName: "db:Entry", Kind: reflect.Slice, Relation: entries, ForeignKey: threadID}, nil},
+			{&structOptions{Name: "Opener", FullName: "db:Entry", Kind: reflect.Struct, Relation: entries, ForeignKey: openerID}, nil},
+		}
+
+		test.Section("Multiple hops")
+		sql, _ := threads.InnerJoin(users).JoinsSql()
+		test.AreEqual(" INNER JOIN entries ON entries.thread_id = threads.id INNER JOIN users ON users.id = entries.user_id", sql)
+
+		test.Section("Aliased relations")
+		sql, _ = threads.LeftJoin(entries).InnerJoin("Opener").JoinsSql()
+		test.AreEqual(" LEFT JOIN entries ON entries.thread_id = threads.id INNER JOIN entries AS opener ON opener.id = threads.opener_id", sql)
+
+		test.Section("Joins of joined Scopes")
+		sql, _ = threads.LeftJoin(entries.InnerJoin(users)).JoinsSql()
+		test.AreEqual(" LEFT JOIN entries ON entries.thread_id = threads.id INNER JOIN users ON users.id = entries.user_id", sql)
+	})
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"errors"
+)
+
+// A rowLock is the locking clause for the rows read by a Scope
+type rowLock struct {
+	// UPDATE or SHARE
+	mode string
+	// blank, NOWAIT or SKIP LOCKED
+	wait string
+}
+
+/*
+ForUpdate locks the rows read by the Scope until the transaction ends, so
+other transactions can't update or lock them. Locking Scopes must be run
+inside a transaction. Add NoWait to return an error instead of waiting for
+rows that are already locked, or SkipLocked to leave them out.
+
+  // claim the next job that nobody else is working on
+  tx.Mapper(Jobs).EqualTo("state", "queued").Order("id").ForUpdate().SkipLocked().Retrieve(&job)
+
+Sqlite locks the whole database for a transaction, so the locking Scopes
+don't change the queries run on sqlite. Count, Exists and the aggregates of a
+locking Scope still need a transaction, but they are run without the lock
+since databases don't allow locking the rows of an aggregate.
+*/
+func (q *queryable) ForUpdate() Scope {
+	return q.withLock("UPDATE", "")
+}
+
+// ForShare locks the rows read by the Scope so that other transactions can
+// read but not update them until the transaction ends
+func (q *queryable) ForShare() Scope {
+	return q.withLock("SHARE", "")
+}
+
+// NoWait makes a locking Scope return an error instead of waiting when rows
+// are locked by another transaction, it implies ForUpdate if the Scope
+// isn't already locking
+func (q *queryable) NoWait() Scope {
+	return q.withLock("", "NOWAIT")
+}
+
+// SkipLocked makes a locking Scope leave out rows that are locked by another
+// transaction, it implies ForUpdate if the Scope isn't already locking
+func (q *queryable) SkipLocked() Scope {
+	return q.withLock("", "SKIP LOCKED")
+}
+
+func (q *queryable) withLock(mode, wait string) Scope {
+	nq := q.Identity().(*queryable)
+	lock := &rowLock{mode: "UPDATE"}
+	if q.lock != nil {
+		*lock = *q.lock
+	}
+	if mode != "" {
+		lock.mode = mode
+	}
+	if wait != "" {
+		lock.wait = wait
+	}
+	nq.lock = lock
+	return nq
+}
+
+// LockSql is the locking clause for the Scope, like FOR UPDATE SKIP LOCKED,
+// it is blank when the Scope doesn't lock rows
+func (q *queryable) LockSql() string {
+	if q.lock == nil {
+		return ""
+	}
+	if q.lock.wait == "" {
+		return "FOR " + q.lock.mode
+	}
+	return "FOR " + q.lock.mode + " " + q.lock.wait
+}
+
+// readErr is the error that a terminal function reading records should
+// return instead of running its query
+func (q *queryable) readErr() error {
+	if q.err != nil {
+		return q.err
+	}
+	if q.lock == nil {
+		return nil
+	}
+	if !q.source.conn.InTransaction() {
+		return errors.New(q.LockSql() + " can only be used inside of a transaction")
+	}
+	_, err := q.source.conn.Dialect.Lock(q)
+	return err
+}
@@ -1,8 +1,75 @@
 package db
 
-// I need to write the code for this, while I'm at I can
-// add a quick performance tweak of a prepared query cache
+import (
+	"fmt"
+	"time"
+)
+
+// A Logger is a subset of a log.Logger, each statement run by a
+// Connection will be passed to Println as a *LogEntry, so a Logger
+// that wants more than the printed line can type assert for it.
 type Logger interface {
 	Println(v ...interface{})
 	Printf(format string, v ...interface{})
 }
+
+/*
+A LogEntry is what is logged for each statement run by a Connection. Statements
+that succeeded are logged to the LOG_QUERY loggers, statements that failed are
+logged to the LOG_ERROR loggers, and both are logged to the LOG_ALL loggers.
+
+  [1.20ms] SELECT posts.id, posts.title FROM posts WHERE posts.id = ? [1] (cached)
+  [310µs] UPDATE posts SET views = ? WHERE posts.id = ? [2 1] 1 rows affected
+  [95µs] SELECT * FROM psots [] error: no such table: psots
+*/
+type LogEntry struct {
+	// The SQL as formatted by the Dialect
+	Query string
+	// The values bound to the query
+	Args []interface{}
+	// How long the statement took to run, for queries returning rows
+	// this does not include the time to read the rows
+	Duration time.Duration
+	// The number of rows changed by the statement, it is -1 when the
+	// statement was not an Exec or the driver doesn't support it
+	RowsAffected int64
+	// Whether the prepared statement was found in the QueryCache
+	Cached bool
+	// The error from running the statement, if any
+	Err error
+}
+
+func (le *LogEntry) String() string {
+	s := fmt.Sprintf("[%v] %s %v", le.Duration, le.Query, le.Args)
+	if le.Cached {
+		s += " (cached)"
+	}
+	if le.RowsAffected >= 0 {
+		s += fmt.Sprintf(" %d rows affected", le.RowsAffected)
+	}
+	if le.Err != nil {
+		s += " error: " + le.Err.Error()
+	}
+	return s
+}
+
+// logging checks whether there are any loggers
+func (c *Connection) logging() bool {
+	return len(c.combinedLogs) > 0 || len(c.queryLogs) > 0 || len(c.errorLogs) > 0
+}
+
+// logStatement sends the LogEntry for a statement to the loggers for its kind
+func (c *Connection) logStatement(entry *LogEntry) {
+	for _, l := range c.combinedLogs {
+		l.Println(entry)
+	}
+	if entry.Err != nil {
+		for _, l := range c.errorLogs {
+			l.Println(entry)
+		}
+	} else {
+		for _, l := range c.queryLogs {
+			l.Println(entry)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/acsellers/assert"
+)
+
+type testLogger struct {
+	entries []*LogEntry
+}
+
+func (tl *testLogger) Println(v ...interface{}) {
+	for _, e := range v {
+		if le, ok := e.(*LogEntry); ok {
+			tl.entries = append(tl.entries, le)
+		}
+	}
+}
+
+func (tl *testLogger) Printf(format string, v ...interface{}) {
+	tl.Println(fmt.Sprintf(format, v...))
+}
+
+func TestFinishStatement(t *testing.T) {
+	Within(t, func(test *Test) {
+		all, queries, errs := &testLogger{}, &testLogger{}, &testLogger{}
+		c := &Connection{}
+		c.SetLogger(all, LOG_ALL)
+		c.SetLogger(queries, LOG_QUERY)
+		c.SetLogger(errs, LOG_ERROR)
+
+		c.finishStatement(context.Background(), "SELECT 1", nil, time.Now(), true, nil, nil)
+		c.finishStatement(context.Background(), "SELECT * FROM psots", []interface{}{1}, time.Now(), false, nil, errors.New("no such table"))
+		test.AreEqual(2, len(all.entries))
+		test.AreEqual(1, len(queries.entries))
+		test.AreEqual(1, len(errs.entries))
+		if len(queries.entries) == 1 && len(errs.entries) == 1 {
+			test.AreEqual("SELECT 1", queries.entries[0].Query)
+			test.IsTrue(queries.entries[0].Cached)
+			test.AreEqual(-1, queries.entries[0].RowsAffected)
+			test.AreEqual("SELECT * FROM psots", errs.entries[0].Query)
+			test.AreEqual("no such table", errs.entries[0].Err.Error())
+		}
+	})
+}
+
+func TestLogEntry(t *testing.T) {
+	Within(t, func(test *Test) {
+		le := &LogEntry{
+			Query:        "UPDATE posts SET views = ? WHERE posts.id = ?",
+			Args:         []interface{}{2, 1},
+			Duration:     time.Millisecond,
+			RowsAffected: 1,
+			Cached:       true,
+		}
+		test.AreEqual("[1ms] UPDATE posts SET views = ? WHERE posts.id = ? [2 1] (cached) 1 rows affected", le.String())
+
+		le = &LogEntry{Query: "SELECT 1", Duration: time.Millisecond, RowsAffected: -1, Err: errors.New("failed")}
+		test.AreEqual("[1ms] SELECT 1 [] error: failed", le.String())
+	})
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -10,10 +11,79 @@ func (s *source) Identity() Scope {
 	return &queryable{source: s}
 }
 
+func (s *source) WithContext(ctx context.Context) Scope {
+	return s.Identity().WithContext(ctx)
+}
+
+func (s *source) OnPrimary() Scope {
+	return s.Identity().OnPrimary()
+}
+
 func (s *source) Where(fragment string, args ...interface{}) Scope {
 	return s.Identity().Where(fragment, args...)
 }
 
+// Or on a Mapper matches the records that match any of the Scopes passed
+func (s *source) Or(scopes ...Scope) Scope {
+	return s.Identity().(*queryable).orWith(false, scopes)
+}
+
+func (s *source) Not(scope Scope) Scope {
+	return s.Identity().Not(scope)
+}
+
+func (s *source) Merge(scope Scope) (Scope, error) {
+	return s.Identity().Merge(scope)
+}
+
+func (s *source) From(table interface{}, alias string) Scope {
+	return s.Identity().From(table, alias)
+}
+
+func (s *source) Columns(columns ...interface{}) Scope {
+	return s.Identity().Columns(columns...)
+}
+
+func (s *source) With(name string, cte Scope) Scope {
+	return s.Identity().With(name, cte)
+}
+
+func (s *source) WithRecursive(name string, anchor, recursive Scope) Scope {
+	return s.Identity().WithRecursive(name, anchor, recursive)
+}
+
+func (s *source) Union(scope Scope) Scope {
+	return s.Identity().Union(scope)
+}
+
+func (s *source) UnionAll(scope Scope) Scope {
+	return s.Identity().UnionAll(scope)
+}
+
+func (s *source) Intersect(scope Scope) Scope {
+	return s.Identity().Intersect(scope)
+}
+
+func (s *source) Except(scope Scope) Scope {
+	return s.Identity().Except(scope)
+}
+
+func (s *source) ForUpdate() Scope {
+	return s.Identity().ForUpdate()
+}
+
+func (s *source) ForShare() Scope {
+	return s.Identity().ForShare()
+}
+
+func (s *source) NoWait() Scope {
+	return s.Identity().NoWait()
+}
+
+func (s *source) SkipLocked() Scope {
+	return s.Identity().SkipLocked()
+}
+
 func (s *source) Having(fragment string, args ...interface{}) Scope {
 	return s.Identity().Having(fragment, args...)
 }
@@ -50,11 +120,11 @@ func (m *source) OrderBy(column, direction string) Scope {
 	return m.Identity().OrderBy(column, direction)
 }
 
-func (m *source) Order(ordering string) Scope {
+func (m *source) Order(ordering interface{}) Scope {
 	return m.Identity().Order(ordering)
 }
 
-func (m *source) Reorder(ordering string) Scope {
+func (m *source) Reorder(ordering interface{}) Scope {
 	return m.Identity().Reorder(ordering)
 }
 
@@ -75,6 +145,50 @@ func (m *source) Count() (int64, error) {
 	return m.Identity().Count()
 }
 
+func (m *source) Exists() (bool, error) {
+	return m.Identity().Exists()
+}
+
+func (m *source) CountOn(column string) (int64, error) {
+	return m.Identity().CountOn(column)
+}
+
+func (m *source) Sum(column string, dest interface{}) error {
+	return m.Identity().Sum(column, dest)
+}
+
+func (m *source) Avg(column string, dest interface{}) error {
+	return m.Identity().Avg(column, dest)
+}
+
+func (m *source) Min(column string, dest interface{}) error {
+	return m.Identity().Min(column, dest)
+}
+
+func (m *source) Max(column string, dest interface{}) error {
+	return m.Identity().Max(column, dest)
+}
+
+func (m *source) CountBy(dest interface{}) error {
+	return m.Identity().CountBy(dest)
+}
+
+func (m *source) SumBy(column string, dest interface{}) error {
+	return m.Identity().SumBy(column, dest)
+}
+
+func (m *source) AvgBy(column string, dest interface{}) error {
+	return m.Identity().AvgBy(column, dest)
+}
+
+func (m *source) MinBy(column string, dest interface{}) error {
+	return m.Identity().MinBy(column, dest)
+}
+
+func (m *source) MaxBy(column string, dest interface{}) error {
+	return m.Identity().MaxBy(column, dest)
+}
+
 func (m *source) Delete() error {
 	return m.Identity().Delete()
 }
@@ -95,6 +209,22 @@ func (m *source) Pluck(column, vals interface{}) error {
 	return m.Identity().Pluck(column, vals)
 }
 
+func (m *source) PluckSeveral(columns []string, vals ...interface{}) error {
+	return m.Identity().PluckSeveral(columns, vals...)
+}
+
+func (m *source) Select(columns string, dest interface{}) error {
+	return m.Identity().Select(columns, dest)
+}
+
+func (m *source) Explain() (*QueryPlan, error) {
+	return m.Identity().Explain()
+}
+
+func (m *source) ExplainAnalyze() (*QueryPlan, error) {
+	return m.Identity().ExplainAnalyze()
+}
+
 func (m *source) Initialize(vals ...interface{}) error {
 	for _, val := range vals {
 		rt := reflect.TypeOf(val)
@@ -150,6 +280,8 @@ func (m *source) initializeSingle(val interface{}) error {
 	mx.instance = val
 	if rv.Elem().Field(m.mixinField).IsNil() {
 		rv.Elem().Field(m.mixinField).Set(reflect.ValueOf(mx))
+	} else if emx, ok := rv.Elem().Field(m.mixinField).Interface().(*Mixin); ok {
+		emx.model = m
 	}
 
 	return nil
@@ -181,6 +313,11 @@ func (m *source) initializeItem(val reflect.Value) error {
 	return nil
 }
 func (m *source) SaveAll(val interface{}) error {
+	return m.SaveAllContext(context.Background(), val)
+}
+
+func (m *source) SaveAllContext(ctx context.Context, val interface{}) error {
+	ctx = withOrigin(ctx, &origin{source: m, method: "SaveAll"})
 	vv := reflect.ValueOf(val)
 	if reflect.TypeOf(val).Kind() == reflect.Ptr {
 		vv = vv.Elem()
@@ -190,19 +327,19 @@ func (m *source) SaveAll(val interface{}) error {
 		return errors.New("Was not passed mappable values")
 	}
 	if vk == reflect.Slice {
-		return m.saveSlice(vv)
+		return m.saveSlice(ctx, vv)
 	} else {
-		return m.saveItem(vv)
+		return m.saveItem(ctx, vv)
 	}
 }
 
-func (m *source) saveSlice(v reflect.Value) error {
+func (m *source) saveSlice(ctx context.Context, v reflect.Value) error {
 	for i := 0; i < v.Len(); i++ {
 		vi := v.Index(i)
 		if vi.Type().Kind() == reflect.Ptr {
 			vi = vi.Elem()
 		}
-		err := m.saveItem(vi)
+		err := m.saveItem(ctx, vi)
 		if err != nil {
 			return err
 		}
@@ -211,16 +348,16 @@ func (m *source) saveSlice(v reflect.Value) error {
 	return nil
 }
 
-func (m *source) saveItem(v reflect.Value) error {
+func (m *source) saveItem(ctx context.Context, v reflect.Value) error {
 	ident := m.extractID(v)
 	if ident == 0 {
-		return m.createItem(v)
+		return m.createItem(ctx, v)
 	}
 	values := m.extractColumnValues(v)
-	return m.EqualTo(m.ID.SqlColumn, ident).UpdateAttributes(values)
+	return m.WithContext(ctx).EqualTo(m.ID.SqlColumn, ident).UpdateAttributes(values)
 }
 
-func (m *source) createItem(v reflect.Value) error {
+func (m *source) createItem(ctx context.Context, v reflect.Value) error {
 	values := m.extractColumnValues(v)
 	for c, _ := range values {
 		if c == m.ID.SqlColumn {
@@ -229,7 +366,7 @@ func (m *source) createItem(v reflect.Value) error {
 	}
 	query, vals := m.conn.Dialect.Create(m, values)
 	if m.conn.Dialect.CreateExec() {
-		result, err := m.runExec(query, vals)
+		result, err := m.runExec(ctx, query, vals)
 		if err != nil {
 			return err
 		}
@@ -239,7 +376,7 @@ func (m *source) createItem(v reflect.Value) error {
 		}
 		m.setIntID(v, newId)
 	} else {
-		result := m.runQueryRow(query, vals)
+		result := m.runQueryRow(ctx, query, vals)
 		if m.ID.Kind == reflect.Int {
 			var newId int64
 			err := result.Scan(&newId)
@@ -354,3 +491,6 @@ func (s *source) RightInclude(include interface{}, nullRecords interface{}) Scop
 func (s *source) IncludeSql(il IncludeList, query string, args ...interface{}) Scope {
 	return s.Identity().IncludeSql(il, query, args...)
 }
+func (s *source) Preload(relations ...interface{}) Scope {
+	return s.Identity().Preload(relations...)
+}
@@ -1,5 +1,9 @@
 package db
 
+import (
+	"context"
+)
+
 type mapperPlus struct {
 	source *source
 	query  Scope
@@ -22,12 +26,121 @@ func (mp *mapperPlus) Dupe() MapperPlus {
 	return &mapperPlus{source: mp.source, query: mp.query.Identity()}
 }
 
+func (mp *mapperPlus) WithContext(ctx context.Context) Scope {
+	mp = mp.identity()
+	mp.query = mp.query.WithContext(ctx)
+	return mp
+}
+
+func (mp *mapperPlus) OnPrimary() Scope {
+	mp = mp.identity()
+	mp.query = mp.query.OnPrimary()
+	return mp
+}
+
 func (mp *mapperPlus) Where(fragment string, args ...interface{}) Scope {
 	mp = mp.identity()
 	mp.query = mp.query.Where(fragment, args...)
 	return mp
 }
 
+func (mp *mapperPlus) Or(scopes ...Scope) Scope {
+	if mp.query == nil {
+		return &mapperPlus{source: mp.source, query: mp.source.Or(scopes...)}
+	}
+	mp = mp.identity()
+	mp.query = mp.query.Or(scopes...)
+	return mp
+}
+
+func (mp *mapperPlus) Not(scope Scope) Scope {
+	mp = mp.identity()
+	mp.query = mp.query.Not(scope)
+	return mp
+}
+
+func (mp *mapperPlus) Merge(scope Scope) (Scope, error) {
+	mp = mp.identity()
+	query, err := mp.query.Merge(scope)
+	if err != nil {
+		return nil, err
+	}
+	mp.query = query
+	return mp, nil
+}
+
+func (mp *mapperPlus) From(table interface{}, alias string) Scope {
+	mp = mp.identity()
+	mp.query = mp.query.From(table, alias)
+	return mp
+}
+
+func (mp *mapperPlus) Columns(columns ...interface{}) Scope {
+	mp = mp.identity()
+	mp.query = mp.query.Columns(columns...)
+	return mp
+}
+
+func (mp *mapperPlus) With(name string, cte Scope) Scope {
+	mp = mp.identity()
+	mp.query = mp.query.With(name, cte)
+	return mp
+}
+
+func (mp *mapperPlus) WithRecursive(name string, anchor, recursive Scope) Scope {
+	mp = mp.identity()
+	mp.query = mp.query.WithRecursive(name, anchor, recursive)
+	return mp
+}
+
+func (mp *mapperPlus) Union(scope Scope) Scope {
+	mp = mp.identity()
+	mp.query = mp.query.Union(scope)
+	return mp
+}
+
+func (mp *mapperPlus) UnionAll(scope Scope) Scope {
+	mp = mp.identity()
+	mp.query = mp.query.UnionAll(scope)
+	return mp
+}
+
+func (mp *mapperPlus) Intersect(scope Scope) Scope {
+	mp = mp.identity()
+	mp.query = mp.query.Intersect(scope)
+	return mp
+}
+
+func (mp *mapperPlus) Except(scope Scope) Scope {
+	mp = mp.identity()
+	mp.query = mp.query.Except(scope)
+	return mp
+}
+
+func (mp *mapperPlus) ForUpdate() Scope {
+	mp = mp.identity()
+	mp.query = mp.query.ForUpdate()
+	return mp
+}
+
+func (mp *mapperPlus) ForShare() Scope {
+	mp = mp.identity()
+	mp.query = mp.query.ForShare()
+	return mp
+}
+
+func (mp *mapperPlus) NoWait() Scope {
+	mp = mp.identity()
+	mp.query = mp.query.NoWait()
+	return mp
+}
+
+func (mp *mapperPlus) SkipLocked() Scope {
+	mp = mp.identity()
+	mp.query = mp.query.SkipLocked()
+	return mp
+}
+
 func (mp *mapperPlus) Having(fragment string, args ...interface{}) Scope {
 	mp = mp.identity()
 	mp.query = mp.query.Having(fragment, args...)
@@ -82,13 +195,13 @@ func (mp *mapperPlus) OrderBy(column, direction string) Scope {
 	return mp
 }
 
-func (mp *mapperPlus) Order(ordering string) Scope {
+func (mp *mapperPlus) Order(ordering interface{}) Scope {
 	mp = mp.identity()
 	mp.query = mp.query.Order(ordering)
 	return mp
 }
 
-func (mp *mapperPlus) Reorder(ordering string) Scope {
+func (mp *mapperPlus) Reorder(ordering interface{}) Scope {
 	mp = mp.identity()
 	mp.query = mp.query.Reorder(ordering)
 	return mp
@@ -98,38 +211,105 @@ func (mp *mapperPlus) Find(id, val interface{}) error {
 	return mp.source.Find(id, val)
 }
 
-func (mp *mapperPlus) SelectorSql() string {
+func (mp *mapperPlus) WithSql() (string, []interface{}) {
+	return mp.identity().query.WithSql()
+}
+func (mp *mapperPlus) SelectorSql() (string, []interface{}) {
 	return mp.identity().query.SelectorSql()
 }
+func (mp *mapperPlus) FromSql() (string, []interface{}) {
+	return mp.identity().query.FromSql()
+}
 func (mp *mapperPlus) ConditionSql() (string, []interface{}) {
 	return mp.identity().query.ConditionSql()
 }
 
-func (mp *mapperPlus) JoinsSql() string {
+func (mp *mapperPlus) JoinsSql() (string, []interface{}) {
 	return mp.identity().query.JoinsSql()
 }
 func (mp *mapperPlus) EndingSql() (string, []interface{}) {
 	return mp.identity().query.EndingSql()
 }
+func (mp *mapperPlus) LockSql() string {
+	return mp.identity().query.LockSql()
+}
 
 func (mp *mapperPlus) Delete() error {
-	return mp.identity().Delete()
+	return mp.identity().query.Delete()
 }
 
 func (mp *mapperPlus) Retrieve(val interface{}) error {
-	return mp.identity().Retrieve(val)
+	return mp.identity().query.Retrieve(val)
 }
 
 func (mp *mapperPlus) RetrieveAll(dest interface{}) error {
-	return mp.identity().RetrieveAll(dest)
+	return mp.identity().query.RetrieveAll(dest)
 }
 
 func (mp *mapperPlus) Count() (int64, error) {
-	return mp.identity().Count()
+	return mp.identity().query.Count()
+}
+
+func (mp *mapperPlus) Exists() (bool, error) {
+	return mp.identity().query.Exists()
+}
+
+func (mp *mapperPlus) CountOn(column string) (int64, error) {
+	return mp.identity().query.CountOn(column)
+}
+
+func (mp *mapperPlus) Sum(column string, dest interface{}) error {
+	return mp.identity().query.Sum(column, dest)
+}
+
+func (mp *mapperPlus) Avg(column string, dest interface{}) error {
+	return mp.identity().query.Avg(column, dest)
+}
+
+func (mp *mapperPlus) Min(column string, dest interface{}) error {
+	return mp.identity().query.Min(column, dest)
+}
+
+func (mp *mapperPlus) Max(column string, dest interface{}) error {
+	return mp.identity().query.Max(column, dest)
+}
+
+func (mp *mapperPlus) CountBy(dest interface{}) error {
+	return mp.identity().query.CountBy(dest)
+}
+
+func (mp *mapperPlus) SumBy(column string, dest interface{}) error {
+	return mp.identity().query.SumBy(column, dest)
+}
+
+func (mp *mapperPlus) AvgBy(column string, dest interface{}) error {
+	return mp.identity().query.AvgBy(column, dest)
+}
+
+func (mp *mapperPlus) MinBy(column string, dest interface{}) error {
+	return mp.identity().query.MinBy(column, dest)
+}
+
+func (mp *mapperPlus) MaxBy(column string, dest interface{}) error {
+	return mp.identity().query.MaxBy(column, dest)
 }
 
 func (mp *mapperPlus) Pluck(column, vals interface{}) error {
-	return mp.identity().Pluck(column, vals)
+	return mp.identity().query.Pluck(column, vals)
+}
+
+func (mp *mapperPlus) PluckSeveral(columns []string, vals ...interface{}) error {
+	return mp.identity().query.PluckSeveral(columns, vals...)
+}
+
+func (mp *mapperPlus) Select(columns string, dest interface{}) error {
+	return mp.identity().query.Select(columns, dest)
+}
+func (mp *mapperPlus) Explain() (*QueryPlan, error) {
+	return mp.identity().query.Explain()
+}
+func (mp *mapperPlus) ExplainAnalyze() (*QueryPlan, error) {
+	return mp.identity().query.ExplainAnalyze()
 }
 func (mp *mapperPlus) TableName() string {
 	return mp.source.TableName()
@@ -140,13 +320,13 @@ func (mp *mapperPlus) PrimaryKeyColumn() string {
 }
 
 func (mp *mapperPlus) UpdateAttribute(column string, val interface{}) error {
-	return mp.identity().UpdateAttribute(column, val)
+	return mp.identity().query.UpdateAttribute(column, val)
 }
 func (mp *mapperPlus) UpdateAttributes(values Attributes) error {
-	return mp.identity().UpdateAttributes(values)
+	return mp.identity().query.UpdateAttributes(values)
 }
 func (mp *mapperPlus) UpdateSql(sql string, vals ...interface{}) error {
-	return mp.identity().UpdateSql(sql, vals...)
+	return mp.identity().query.UpdateSql(sql, vals...)
 }
 func (mp *mapperPlus) Initialize(val ...interface{}) error {
 	return mp.source.Initialize(val...)
@@ -154,6 +334,9 @@ func (mp *mapperPlus) Initialize(val ...interface{}) error {
 func (mp *mapperPlus) SaveAll(val interface{}) error {
 	return mp.source.SaveAll(val)
 }
+func (mp *mapperPlus) SaveAllContext(ctx context.Context, val interface{}) error {
+	return mp.source.SaveAllContext(ctx, val)
+}
 func (mp *mapperPlus) LeftJoin(joins ...interface{}) Scope {
 	mp = mp.identity()
 	mp.query = mp.query.LeftJoin(joins...)
@@ -205,3 +388,8 @@ func (mp *mapperPlus) IncludeSql(il IncludeList, query string, args ...interface
 	mp.query = mp.query.IncludeSql(il, query, args...)
 	return mp
 }
+func (mp *mapperPlus) Preload(relations ...interface{}) Scope {
+	mp = mp.identity()
+	mp.query = mp.query.Preload(relations...)
+	return mp
+}
@@ -1,6 +1,8 @@
 package db
 
 import (
+	"context"
+	"strings"
 	"testing"
 	. "github.com/acsellers/assert"
 )
@@ -170,6 +172,302 @@ func TestWhere(t *testing.T) {
 	})
 }
 
+func TestOrNot(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+			Users := c.m("User")
+
+			var posts []post
+			test.NoError(Posts.EqualTo("id", 1).Or(Posts.EqualTo("id", 2)).RetrieveAll(&posts))
+			test.AreEqual(2, len(posts))
+
+			test.NoError(Posts.Where("views >= ?", 0).Not(Posts.EqualTo("id", 1)).RetrieveAll(&posts))
+			test.AreEqual(1, len(posts))
+			if len(posts) == 1 {
+				test.AreEqual("Second Post", posts[0].Title)
+			}
+
+			scope := Posts.EqualTo("id", 1).EqualTo("views", 1).Or(Posts.EqualTo("id", 2)).(*queryable)
+			fragment, values := scope.ConditionSql()
+			test.AreEqual("((id = ? AND views = ?) OR (id = ?))", fragment)
+			test.AreEqual([]interface{}{1, 1, 2}, values)
+			fragment, _ = Posts.Not(Posts.EqualTo("id", 1).Or(Posts.EqualTo("id", 2))).(*queryable).ConditionSql()
+			test.AreEqual("(NOT ((id = ?) OR (id = ?)))", fragment)
+
+			test.NoError(Posts.Or(Posts.EqualTo("id", 1), Posts.EqualTo("id", 2)).RetrieveAll(&posts))
+			test.AreEqual(2, len(posts))
+			ct, e := Posts.Or(Posts.EqualTo("id", 1)).Count()
+			test.NoError(e)
+			test.AreEqual(1, ct)
+			ct, e = Posts.Order("id").Or(Posts.EqualTo("id", 1)).Count()
+			test.NoError(e)
+			test.AreEqual(2, ct)
+
+			test.IsNotNil(Posts.Or(Users.EqualTo("id", 1)).RetrieveAll(&posts))
+			_, e = Posts.Not(Users.EqualTo("id", 1)).Count()
+			test.IsNotNil(e)
+		}
+	})
+}
+
+func TestMerge(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+			Users := c.m("User")
+
+			test.Section("Merging Scopes")
+			viewed := Posts.Cond("views", GTE, 0).Order("id ASC")
+			latest := Posts.Where("title LIKE ?", "%Post%").Reorder("views DESC").Limit(1).Offset(1)
+			merged, e := viewed.Merge(latest)
+			test.NoError(e)
+			mq := merged.(*queryable)
+			test.AreEqual([]string{"id ASC", "views DESC"}, mq.order)
+			test.AreEqual(1, mq.limit)
+			test.AreEqual(1, mq.offset)
+			test.AreEqual(2, len(mq.conditions))
+
+			var posts []post
+			test.NoError(merged.RetrieveAll(&posts))
+			test.AreEqual(1, len(posts))
+			if len(posts) == 1 {
+				test.AreEqual("Second Post", posts[0].Title)
+			}
+
+			test.Section("Merging doesn't change the Scopes")
+			test.AreEqual(1, len(viewed.(*queryable).conditions))
+			test.AreEqual(0, viewed.(*queryable).limit)
+
+			test.Section("Cloned Scopes don't share slices")
+			base := Posts.EqualTo("id", 1).GroupBy("id").Having("COUNT(*) > ?", 0)
+			first := base.EqualTo("views", 1).(*queryable)
+			second := base.EqualTo("views", 2).(*queryable)
+			test.AreEqual(1, first.conditions[1].Values()[0])
+			test.AreEqual(2, second.conditions[1].Values()[0])
+			test.AreEqual("id", second.groupBy)
+			test.AreEqual(1, len(second.having))
+
+			test.Section("Merging Scopes for different Mappers")
+			_, e = Posts.Merge(Users.EqualTo("id", 1))
+			test.IsNotNil(e)
+		}
+	})
+}
+
+func TestSubqueries(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+			Users := c.m("User")
+
+			test.Section("Scopes as In items")
+			var users []user
+			test.NoError(Users.In("id", Posts.Cond("views", GTE, 1).Columns("user_id")).RetrieveAll(&users))
+			test.AreEqual(1, len(users))
+
+			test.Section("Scopes as Where values")
+			ct, e := Users.Where("EXISTS (?)", Posts.Where("posts.user_id = users.id")).Count()
+			test.NoError(e)
+			test.AreEqual(1, ct)
+			ct, e = Users.Where("id = :id:", map[string]interface{}{"id": Posts.Columns("user_id").EqualTo("id", 1)}).Count()
+			test.NoError(e)
+			test.AreEqual(1, ct)
+
+			test.Section("Scopes as Cond and EqualTo values")
+			var posts []post
+			test.NoError(Posts.Cond("id", LT, Posts.Columns(Func("MAX(id)"))).RetrieveAll(&posts))
+			test.AreEqual(1, len(posts))
+			if len(posts) == 1 {
+				test.AreEqual("First Post", posts[0].Title)
+			}
+			test.NoError(Posts.EqualTo("user_id", Users.Columns("id").EqualTo("name", "wat")).RetrieveAll(&posts))
+			test.AreEqual(1, len(posts))
+
+			test.Section("Values are kept in order")
+			scope := Posts.Where("views >= ?", 0).In("user_id", Users.Columns("id").EqualTo("name", "wat")).EqualTo("id", 1)
+			fragment, values := scope.ConditionSql()
+			test.AreEqual("(views >= ? AND user_id IN (SELECT users.id FROM users WHERE (name = ?)) AND id = ?)", fragment)
+			test.AreEqual([]interface{}{0, "wat", 1}, values)
+
+			test.Section("Scopes as derived tables")
+			latest := Posts.From(Posts.Order("id DESC").Limit(1), "latest")
+			test.NoError(latest.RetrieveAll(&posts))
+			test.AreEqual(1, len(posts))
+			if len(posts) == 1 {
+				test.AreEqual("Second Post", posts[0].Title)
+			}
+			ct, e = latest.Count()
+			test.NoError(e)
+			test.AreEqual(1, ct)
+
+			test.Section("Tables in From")
+			aliased := Posts.From("posts", "p").EqualTo("p.id", 1)
+			_, values = selectSql(aliased)
+			test.AreEqual([]interface{}{1}, values)
+			test.NoError(aliased.RetrieveAll(&posts))
+			test.AreEqual(1, len(posts))
+			if len(posts) == 1 {
+				test.AreEqual("First Post", posts[0].Title)
+			}
+			ct, e = aliased.Count()
+			test.NoError(e)
+			test.AreEqual(1, ct)
+
+			test.Section("Errors in subqueries")
+			test.IsNotNil(Posts.In("id", Posts.Or(Users.EqualTo("id", 1))).RetrieveAll(&posts))
+		}
+	})
+}
+
+func TestCommonTables(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+			Users := c.m("User")
+
+			test.Section("With")
+			var posts []post
+			authors := Users.EqualTo("name", "wat")
+			scope := Posts.With("authors", authors).JoinSql("INNER JOIN authors ON authors.id = posts.user_id")
+			test.NoError(scope.RetrieveAll(&posts))
+			test.AreEqual(1, len(posts))
+			with, values := scope.WithSql()
+			test.AreEqual("WITH authors AS (SELECT users.id, users.name, users.email, users.password, users.story FROM users WHERE (name = ?)) ", with)
+			test.AreEqual([]interface{}{"wat"}, values)
+
+			ct, e := Posts.With("first", Posts.EqualTo("id", 1)).In("id", Posts.From("first", "").Columns("id")).Count()
+			test.NoError(e)
+			test.AreEqual(1, ct)
+			first := Posts.With("first", Posts.EqualTo("id", 1)).From("first", "f")
+			test.NoError(first.RetrieveAll(&posts))
+			test.AreEqual(1, len(posts))
+			if len(posts) == 1 {
+				test.AreEqual("First Post", posts[0].Title)
+			}
+
+			test.Section("WithRecursive")
+			chain := Posts.WithRecursive(
+				"chain",
+				Posts.EqualTo("id", 1),
+				Posts.JoinSql("INNER JOIN chain ON posts.id = chain.id + 1"),
+			).From("chain", "")
+			_, values = selectSql(chain)
+			test.AreEqual([]interface{}{1}, values)
+			test.NoError(chain.OrderBy("id", "ASC").RetrieveAll(&posts))
+			test.AreEqual(2, len(posts))
+			if len(posts) == 2 {
+				test.AreEqual("Second Post", posts[1].Title)
+			}
+		}
+	})
+}
+
+func TestWindows(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+
+			test.Section("Selecting a Window into a field")
+			var posts []post
+			rank := Func("ROW_NUMBER()").Over("", "id DESC").As("views")
+			test.NoError(Posts.Columns("id", "title", rank).OrderBy("id", "ASC").RetrieveAll(&posts))
+			test.AreEqual(2, len(posts))
+			if len(posts) == 2 {
+				test.AreEqual(2, posts[0].Views)
+				test.AreEqual(1, posts[1].Views)
+				test.AreEqual("Second Post", posts[1].Title)
+				test.AreEqual("", posts[1].Body)
+			}
+
+			test.Section("Filtering on a Window")
+			latest := Func("ROW_NUMBER()").Over("user_id", "id DESC").As("rn")
+			ranked := Posts.From(Posts.Columns("*", latest), "ranked").EqualTo("rn", 1)
+			test.NoError(ranked.OrderBy("id", "ASC").RetrieveAll(&posts))
+			test.AreEqual(2, len(posts))
+			if len(posts) == 2 {
+				test.AreEqual("This is the first-est post", posts[0].Body)
+			}
+
+			test.Section("Ordering by a Window")
+			var first post
+			test.NoError(Posts.Order(Func("ROW_NUMBER()").Over("", "id DESC")).Retrieve(&first))
+			test.AreEqual("Second Post", first.Title)
+			byTitle := Func("SUM(CASE WHEN title = ? THEN 1 ELSE 0 END)", "First Post").Over("", "id")
+			test.NoError(Posts.Order(byTitle).OrderBy("id", "DESC").Retrieve(&first))
+			test.AreEqual("Second Post", first.Title)
+			_, values := selectSql(Posts.Order(byTitle))
+			test.AreEqual([]interface{}{"First Post"}, values)
+
+			test.Section("Window values are bound")
+			quoted := Func("SUM(CASE WHEN title = ? THEN 1 ELSE 0 END)", "it's").Over("", "id").As("views")
+			selection, values := Posts.Columns("id", quoted).(*queryable).SelectorSql()
+			test.AreEqual("posts.id, SUM(CASE WHEN title = ? THEN 1 ELSE 0 END) OVER (ORDER BY id) AS views", selection)
+			test.AreEqual([]interface{}{"it's"}, values)
+			test.NoError(Posts.Columns("id", quoted).RetrieveAll(&posts))
+			test.AreEqual(2, len(posts))
+			var views []int
+			test.NoError(Posts.Pluck(Func("views + ?", 1), &views))
+			test.AreEqual(2, len(views))
+		}
+	})
+}
+
+func TestSetOperations(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+			Users := c.m("User")
+			first, second := Posts.EqualTo("id", 1), Posts.EqualTo("id", 2)
+
+			test.Section("Union")
+			var posts []post
+			test.NoError(first.Union(second).RetrieveAll(&posts))
+			test.AreEqual(2, len(posts))
+			ct, e := Posts.UnionAll(first).Count()
+			test.NoError(e)
+			test.AreEqual(3, ct)
+
+			test.Section("Ordering and limiting the combined records")
+			test.NoError(first.Union(second).OrderBy("id", "DESC").Limit(1).RetrieveAll(&posts))
+			test.AreEqual(1, len(posts))
+			if len(posts) == 1 {
+				test.AreEqual("Second Post", posts[0].Title)
+			}
+			test.NoError(Posts.OrderBy("id", "DESC").Limit(1).Union(first).RetrieveAll(&posts))
+			test.AreEqual(2, len(posts))
+
+			test.Section("Values are kept in order")
+			scope := first.Union(second).EqualTo("views", 3)
+			from, values := scope.FromSql()
+			test.IsTrue(strings.Contains(from, " UNION "))
+			test.AreEqual([]interface{}{1, 2}, values)
+			_, values = scope.ConditionSql()
+			test.AreEqual([]interface{}{3}, values)
+
+			if c.Dialect.SetOperation("INTERSECT") == nil {
+				test.Section("Intersect and Except")
+				test.NoError(Posts.Intersect(first).RetrieveAll(&posts))
+				test.AreEqual(1, len(posts))
+				test.NoError(Posts.Except(first).RetrieveAll(&posts))
+				test.AreEqual(1, len(posts))
+				if len(posts) == 1 {
+					test.AreEqual("Second Post", posts[0].Title)
+				}
+			}
+
+			test.Section("Incompatible Scopes")
+			test.IsNotNil(Posts.Union(Posts.Columns("id")).RetrieveAll(&posts))
+			test.IsNotNil(Posts.Union(Users.Identity()).RetrieveAll(&posts))
+			test.IsNotNil(Posts.Columns("id").Union(Posts.Columns("title")).RetrieveAll(&posts))
+		}
+
+		test.Section("Unsupported set operations")
+		test.IsNotNil(newMysql().SetOperation("EXCEPT"))
+		test.IsNil(newMysql().SetOperation("UNION ALL"))
+	})
+}
+
 func TestCount(t *testing.T) {
 	Within(t, func(test *Test) {
 		for _, c := range availableTestConns() {
@@ -186,9 +484,44 @@ func TestCount(t *testing.T) {
 			test.NoError(e)
 			test.AreEqual(0, c)
 
+			c, e = Posts.GroupBy("user_id").Count()
+			test.NoError(e)
+			test.AreEqual(2, c)
+
+			c, e = Posts.Order("id").Limit(1).Count()
+			test.NoError(e)
+			test.AreEqual(1, c)
+
+			c, e = Posts.Order("id").Limit(5).Offset(1).Count()
+			test.NoError(e)
+			test.AreEqual(1, c)
+
+			c, e = Posts.Columns("DISTINCT views").Count()
+			test.NoError(e)
+			test.AreEqual(1, c)
+
+		}
+	})
+}
+func TestExists(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+			found, e := Posts.EqualTo("id", 1).Exists()
+			test.NoError(e)
+			test.IsTrue(found)
+
+			found, e = Posts.EqualTo("title", "banana").Exists()
+			test.NoError(e)
+			test.IsFalse(found)
+
+			found, e = Posts.GroupBy("user_id").Having("COUNT(*) > ?", 1).Exists()
+			test.NoError(e)
+			test.IsFalse(found)
 		}
 	})
 }
+
 func TestSave(t *testing.T) {
 	Within(t, func(test *Test) {
 		for _, c := range availableTestConns() {
@@ -235,3 +568,96 @@ func TestPluck(t *testing.T) {
 		}
 	})
 }
+
+func TestPluckSeveral(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+			var titles []string
+			var ids []int
+			test.NoError(Posts.OrderBy("id", "ASC").PluckSeveral([]string{"title", "id"}, &titles, &ids))
+			test.AreEqual([]string{"First Post", "Second Post"}, titles)
+			test.AreEqual([]int{1, 2}, ids)
+
+			test.IsNotNil(Posts.PluckSeveral([]string{"title", "id"}, &titles))
+		}
+	})
+}
+
+func TestCountOn(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+			ct, e := Posts.CountOn("DISTINCT views")
+			test.NoError(e)
+			test.AreEqual(1, ct)
+
+			ct, e = Posts.EqualTo("id", 1).CountOn("user_id")
+			test.NoError(e)
+			test.AreEqual(1, ct)
+		}
+	})
+}
+
+func TestSelect(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+
+			test.Section("Selecting into anonymous structs")
+			var stats []struct {
+				UserId     int
+				TotalViews float64
+				Titles     string
+			}
+			test.NoError(Posts.GroupBy("user_id").OrderBy("user_id", "ASC").Select(
+				"posts.user_id, SUM(views) AS total_views, MAX(title) AS titles", &stats,
+			))
+			test.AreEqual(2, len(stats))
+			if len(stats) == 2 {
+				test.AreEqual(1, stats[1].UserId)
+				test.AreEqual(1, stats[1].TotalViews)
+				test.AreEqual("First Post", stats[1].Titles)
+			}
+
+			test.Section("Selecting NULLs and embedded structs")
+			type titled struct {
+				Title string
+			}
+			var story struct {
+				titled
+				Story   string
+				Missing int
+			}
+			test.NoError(Posts.EqualTo("id", 2).Select("title, NULL AS story", &story))
+			test.AreEqual("Second Post", story.Title)
+			test.AreEqual("", story.Story)
+
+			var pointers []*titled
+			test.NoError(Posts.OrderBy("id", "ASC").Select("title", &pointers))
+			test.AreEqual(2, len(pointers))
+
+			test.Section("Selecting nothing into a struct")
+			test.IsNotNil(Posts.EqualTo("id", 0).Select("title", &story))
+		}
+	})
+}
+
+func TestWithContext(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+
+			var posts []post
+			test.NoError(Posts.WithContext(context.Background()).RetrieveAll(&posts))
+			test.AreEqual(2, len(posts))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			test.IsNotNil(Posts.WithContext(ctx).RetrieveAll(&posts))
+			_, e := Posts.EqualTo("id", 1).WithContext(ctx).Count()
+			test.IsNotNil(e)
+			test.IsNotNil(Posts.WithContext(ctx).EqualTo("id", 1).UpdateAttribute("views", 5))
+		}
+	})
+}
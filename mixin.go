@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 )
@@ -46,8 +47,11 @@ func (m *Mixin) Init(instance interface{}) error {
 }
 
 // Manually initialize a struct that is mapped on multiple connections.
+// This will also move an already initialized instance to the Connection,
+// so it can be saved inside of a transaction.
 //
 //  user.InitWithConn(pgConn, &user)
+//  user.InitWithConn(tx, &user)
 func (m *Mixin) InitWithConn(conn *Connection, instance interface{}) error {
 	tn := fullNameFor(getType(instance))
 	s := conn.mappedStructs[tn]
@@ -55,7 +59,7 @@ func (m *Mixin) InitWithConn(conn *Connection, instance interface{}) error {
 		return fmt.Errorf("Could not locate a mapper for this struct")
 	}
 
-	s.Initialize(instance)
+	conn.bind(s).Initialize(instance)
 
 	return nil
 }
@@ -71,6 +75,13 @@ func (m *Mixin) Save() error {
 	return m.model.SaveAll(m.instance)
 }
 
+// SaveContext is Save with a context to cancel the query or set a deadline for it.
+//
+//  e := user.SaveContext(req.Context())
+func (m *Mixin) SaveContext(ctx context.Context) error {
+	return m.model.SaveAllContext(ctx, m.instance)
+}
+
 func (m *Mixin) selfScope() Scope {
 	id := m.model.extractID(reflect.ValueOf(m.instance).Elem())
 	return m.model.EqualTo(m.model.ID.SqlColumn, id)
@@ -83,6 +94,13 @@ func (m *Mixin) Delete() error {
 	return m.selfScope().Delete()
 }
 
+// DeleteContext is Delete with a context to cancel the query or set a deadline for it.
+//
+//  user.DeleteContext(req.Context())
+func (m *Mixin) DeleteContext(ctx context.Context) error {
+	return m.selfScope().WithContext(ctx).Delete()
+}
+
 // Update the database record record with the column name attr with the value passed
 // Note: the instance that you are calling this on will not get the updated values
 //
@@ -102,6 +120,34 @@ func (m *Mixin) UpdateAttributes(values Attributes) error {
 	return m.selfScope().UpdateAttributes(values)
 }
 
+// Associate links the instance to the records through the join table of a has
+// and belongs to many relation. The records may be structs, pointers to structs
+// or primary keys of the related mapper.
+//
+//  post.Associate("Tags", &golang, &databases)
+func (m *Mixin) Associate(relation string, records ...interface{}) error {
+	return m.model.associate(context.Background(), m.instance, relation, records)
+}
+
+// AssociateContext is Associate with a context to cancel the query or set a deadline for it.
+func (m *Mixin) AssociateContext(ctx context.Context, relation string, records ...interface{}) error {
+	return m.model.associate(ctx, m.instance, relation, records)
+}
+
+// Dissociate removes the links between the instance and the records from the join
+// table of a has and belongs to many relation, or every link of the instance when
+// no records are passed.
+//
+//  post.Dissociate("Tags", &databases)
+func (m *Mixin) Dissociate(relation string, records ...interface{}) error {
+	return m.model.dissociate(context.Background(), m.instance, relation, records)
+}
+
+// DissociateContext is Dissociate with a context to cancel the query or set a deadline for it.
+func (m *Mixin) DissociateContext(ctx context.Context, relation string, records ...interface{}) error {
+	return m.model.dissociate(ctx, m.instance, relation, records)
+}
+
 // Return whether a column had the value NULL when retrieved from the database
 // In this manner, you don't need to use sql.NullString, or *string values in your
 // structs for fields that may be nullable in the database.
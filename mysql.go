@@ -1,11 +1,16 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
 var typeRegex = regexp.MustCompile("^([a-zA-Z0-9]+)(\\([0-9]+\\))?(.*)")
@@ -109,3 +114,203 @@ func (d mysqlDialect) sqlLengthFrom(st string) int {
 
 	return 0
 }
+
+// Mysql allows grouping by a column that determines the rest of the
+// selected columns, so the GROUP BY isn't expanded
+func (d mysqlDialect) ExpandGroupBy() bool {
+	return false
+}
+
+// Mysql doesn't have FULL OUTER JOIN, so a Scope with one is queried as
+// the rows with a LEFT JOIN combined with the rows of a RIGHT JOIN that
+// have no record for the Mapper
+func (d mysqlDialect) Query(scope Scope) (string, []interface{}) {
+	if q, ok := scope.(*queryable); ok && q.fullJoined() {
+		return d.Base.Query(q.fullJoinUnion())
+	}
+	return d.Base.Query(scope)
+}
+
+// INTERSECT and EXCEPT were only added in MySQL 8.0.31, so only UNION is
+// allowed
+func (d mysqlDialect) SetOperation(op string) error {
+	if op == "INTERSECT" || op == "EXCEPT" {
+		return errors.New("mysql does not support " + op)
+	}
+	return nil
+}
+
+// Mysql errors 1213 (deadlock found) and 1205 (lock wait timeout exceeded)
+// can be retried
+func (d mysqlDialect) RetryableError(err error) bool {
+	var me *mysql.MySQLError
+	if errors.As(err, &me) {
+		return me.Number == 1213 || me.Number == 1205
+	}
+	return false
+}
+
+var (
+	mysqlPlanCost   = regexp.MustCompile(`\(cost=([0-9.e+]+) rows=([0-9.e+]+)\)`)
+	mysqlPlanActual = regexp.MustCompile(`\(actual time=[0-9.e+]+\.\.([0-9.e+]+) rows=([0-9.e+]+)`)
+	mysqlPlanTable  = regexp.MustCompile(` on (\S+)`)
+	mysqlPlanIndex  = regexp.MustCompile(` using (\S+)`)
+)
+
+// Mysql plans are requested as JSON, since only the JSON and tree formats
+// have the cost, and EXPLAIN ANALYZE is always returned as a tree
+func (d mysqlDialect) Explain(query string, analyze bool) (string, error) {
+	if analyze {
+		return "EXPLAIN ANALYZE " + query, nil
+	}
+	return "EXPLAIN FORMAT=JSON " + query, nil
+}
+
+// Both formats return a single row, the JSON plan is an object while the
+// tree is indented lines
+func (d mysqlDialect) ParsePlan(rows *sql.Rows) (*QueryPlan, error) {
+	results, err := scanStrings(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != 1 {
+		return nil, errors.New("mysql returned " + strconv.Itoa(len(results)) + " rows for the plan")
+	}
+	explain := strings.TrimSpace(results[0]["explain"])
+	if strings.HasPrefix(explain, "{") {
+		return parseMysqlJSON(explain)
+	}
+	return parseMysqlTree(explain), nil
+}
+
+// a mysqlBlock is a query block of a JSON plan, or an operation within it,
+// the tables of a join are each a block in the nested loop
+type mysqlBlock struct {
+	CostInfo          mysqlCost    `json:"cost_info"`
+	Table             *mysqlTable  `json:"table"`
+	NestedLoop        []mysqlBlock `json:"nested_loop"`
+	OrderingOperation *mysqlBlock  `json:"ordering_operation"`
+	GroupingOperation *mysqlBlock  `json:"grouping_operation"`
+	DuplicatesRemoval *mysqlBlock  `json:"duplicates_removal"`
+}
+
+// mysql reports costs as strings
+type mysqlCost struct {
+	QueryCost  string `json:"query_cost"`
+	PrefixCost string `json:"prefix_cost"`
+}
+
+type mysqlTable struct {
+	TableName           string    `json:"table_name"`
+	AccessType          string    `json:"access_type"`
+	Key                 string    `json:"key"`
+	RowsExaminedPerScan float64   `json:"rows_examined_per_scan"`
+	AttachedCondition   string    `json:"attached_condition"`
+	CostInfo            mysqlCost `json:"cost_info"`
+}
+
+func (mt *mysqlTable) node() *PlanNode {
+	pn := &PlanNode{
+		Type:   mt.AccessType,
+		Table:  mt.TableName,
+		Index:  mt.Key,
+		Rows:   mt.RowsExaminedPerScan,
+		Detail: mt.AttachedCondition,
+	}
+	pn.Cost, _ = strconv.ParseFloat(mt.CostInfo.PrefixCost, 64)
+	return pn
+}
+
+// steps are the nodes for the tables and operations of the block
+func (mb *mysqlBlock) steps() []*PlanNode {
+	var steps []*PlanNode
+	if mb.Table != nil {
+		steps = append(steps, mb.Table.node())
+	}
+	for i := range mb.NestedLoop {
+		steps = append(steps, mb.NestedLoop[i].steps()...)
+	}
+	operations := []struct {
+		name  string
+		block *mysqlBlock
+	}{
+		{"ordering_operation", mb.OrderingOperation},
+		{"grouping_operation", mb.GroupingOperation},
+		{"duplicates_removal", mb.DuplicatesRemoval},
+	}
+	for _, op := range operations {
+		if op.block != nil {
+			steps = append(steps, &PlanNode{Type: op.name, Detail: op.name, Children: op.block.steps()})
+		}
+	}
+	return steps
+}
+
+// parseMysqlJSON reads a JSON plan into a node for the query block, with
+// the cost of the whole query, that holds the steps of the query
+func parseMysqlJSON(doc string) (*QueryPlan, error) {
+	var explained struct {
+		QueryBlock mysqlBlock `json:"query_block"`
+	}
+	if err := json.Unmarshal([]byte(doc), &explained); err != nil {
+		return nil, err
+	}
+
+	qb := explained.QueryBlock
+	pn := &PlanNode{Type: "query_block", Detail: "query_block", Children: qb.steps()}
+	pn.Cost, _ = strconv.ParseFloat(qb.CostInfo.QueryCost, 64)
+	return &QueryPlan{Nodes: []*PlanNode{pn}}, nil
+}
+
+func parseMysqlTree(tree string) *QueryPlan {
+	plan := &QueryPlan{}
+	var parents []*PlanNode
+	for _, line := range strings.Split(tree, "\n") {
+		indent := strings.Index(line, "-> ")
+		if indent < 0 {
+			continue
+		}
+		detail := line[indent+3:]
+		pn := &PlanNode{Detail: detail}
+
+		desc := detail
+		if i := strings.Index(desc, "  ("); i >= 0 {
+			desc = desc[:i]
+		}
+		if i := strings.Index(desc, " on "); i >= 0 {
+			pn.Type = desc[:i]
+		} else if i := strings.Index(desc, ":"); i >= 0 {
+			pn.Type = desc[:i]
+		} else {
+			pn.Type = desc
+		}
+		if m := mysqlPlanTable.FindStringSubmatch(desc); m != nil {
+			pn.Table = m[1]
+		}
+		if m := mysqlPlanIndex.FindStringSubmatch(desc); m != nil {
+			pn.Index = m[1]
+		}
+		if m := mysqlPlanCost.FindStringSubmatch(detail); m != nil {
+			pn.Cost, _ = strconv.ParseFloat(m[1], 64)
+			pn.Rows, _ = strconv.ParseFloat(m[2], 64)
+		}
+		if m := mysqlPlanActual.FindStringSubmatch(detail); m != nil {
+			ms, _ := strconv.ParseFloat(m[1], 64)
+			pn.ActualTime = time.Duration(ms * float64(time.Millisecond))
+			pn.ActualRows, _ = strconv.ParseFloat(m[2], 64)
+		}
+
+		depth := indent / 4
+		if depth > len(parents) {
+			depth = len(parents)
+		}
+		parents = parents[:depth]
+		if depth == 0 {
+			plan.Nodes = append(plan.Nodes, pn)
+		} else {
+			parents[depth-1].Children = append(parents[depth-1].Children, pn)
+		}
+		parents = append(parents, pn)
+	}
+	return plan
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"fmt"
 	"reflect"
 )
 
@@ -12,6 +13,16 @@ func (c *Connection) CreateMapper(name string, mapee interface{}) (Mapper, error
 
 	c.createRelations(ms)
 
+	// the keys of a relation from a struct to itself need different names
+	for _, r := range ms.relations {
+		if r.Through != nil && r.Through.Key == r.Through.ForeignKey {
+			return nil, fmt.Errorf(
+				"Both keys of the join table for %s.%s are %s, name them with db_join_key and db_join_foreign_key tags",
+				ms.Name, r.structOptions.Name, r.Through.Key,
+			)
+		}
+	}
+
 	return ms, nil
 }
 
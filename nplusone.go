@@ -0,0 +1,252 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/*
+An NPlusOneDetector watches for the same statement being run over and over
+with different primary or foreign keys inside of a unit of work, which
+usually means records are being loaded one at a time in a loop when they
+could be loaded with a single query. Statements are only watched when
+they are run with a context from UnitOfWork.
+
+  conn.NPlusOne = &db.NPlusOneDetector{Threshold: 3, Fail: true}
+
+  ctx := db.UnitOfWork(req.Context())
+  Posts.WithContext(ctx).RetrieveAll(&posts)
+  for _, post := range posts {
+    // the 4th Find returns an *NPlusOneError naming Post.User
+    Users.WithContext(ctx).Find(post.UserId, &post.User)
+  }
+
+When Fail isn't set, the *NPlusOneError is passed to Warn, or when Warn is
+nil, to the LOG_ERROR and LOG_ALL loggers of the Connection.
+*/
+type NPlusOneDetector struct {
+	// The number of times a statement may be run with different keys in
+	// a unit of work before it is reported
+	Threshold int
+	// When Fail is set, statements past the Threshold with a key that
+	// hasn't been seen in the unit of work are not run and return an
+	// *NPlusOneError
+	Fail bool
+	// Warn is called once for each statement that passes the Threshold
+	// when Fail isn't set
+	Warn func(err *NPlusOneError)
+}
+
+// An NPlusOneError describes a statement that was run more times than the
+// NPlusOneDetector's Threshold allows
+type NPlusOneError struct {
+	// The fingerprint of the statement, see Fingerprint
+	Query string
+	// The Mapper and terminal function that ran the statement
+	Mapper string
+	Method string
+	// The key columns that had a different value each time
+	Columns []string
+	// The number of times the statement was run in the unit of work
+	Count int
+	// The file and line that ran the statement the last time
+	CallSite string
+	// The relation that could have loaded the records in one query, like
+	// Post.User, it is empty when no relation was found
+	Relation string
+}
+
+func (npe *NPlusOneError) Error() string {
+	msg := fmt.Sprintf(
+		"N+1 query: %s.%s ran %d times with different %s at %s: %s",
+		npe.Mapper, npe.Method, npe.Count, strings.Join(npe.Columns, ", "), npe.CallSite, npe.Query,
+	)
+	if npe.Relation != "" {
+		msg += ", try including or preloading " + npe.Relation
+	}
+	return msg
+}
+
+type unitOfWorkKey struct{}
+
+type unitOfWork struct {
+	sync.Mutex
+	statements map[string]*unitStatement
+}
+
+type unitStatement struct {
+	keys     map[string]bool
+	reported bool
+}
+
+// UnitOfWork returns a context that the NPlusOneDetector of a Connection
+// will use to group statements, like the statements run for a request or a
+// test. Statements run with contexts from different calls to UnitOfWork
+// are counted separately.
+func UnitOfWork(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unitOfWorkKey{}, &unitOfWork{
+		statements: make(map[string]*unitStatement),
+	})
+}
+
+// detectNPlusOne counts the statement in its unit of work, returning an
+// error when the statement should not be run
+func (s *source) detectNPlusOne(st *Statement) error {
+	detector := s.conn.NPlusOne
+	if detector == nil {
+		return nil
+	}
+	unit, ok := st.Context.Value(unitOfWorkKey{}).(*unitOfWork)
+	if !ok {
+		return nil
+	}
+	o := originOf(st.Context)
+	if o == nil || o.scope == nil {
+		return nil
+	}
+	columns, values := o.scope.keyConditions()
+	if len(columns) == 0 {
+		return nil
+	}
+
+	fingerprint := Fingerprint(st.Query)
+	unit.Lock()
+	us, ok := unit.statements[o.method+":"+fingerprint]
+	if !ok {
+		us = &unitStatement{keys: make(map[string]bool)}
+		unit.statements[o.method+":"+fingerprint] = us
+	}
+	key := fmt.Sprint(values...)
+	seen := us.keys[key]
+	us.keys[key] = true
+	count := len(us.keys)
+	report := count > detector.Threshold && !seen && (detector.Fail || !us.reported)
+	us.reported = us.reported || report
+	unit.Unlock()
+	if !report {
+		return nil
+	}
+
+	err := &NPlusOneError{
+		Query:    fingerprint,
+		Mapper:   s.Name,
+		Method:   o.method,
+		Columns:  columns,
+		Count:    count,
+		CallSite: callSite(),
+		Relation: s.relationFor(columns),
+	}
+	if detector.Fail {
+		return err
+	}
+	if detector.Warn != nil {
+		detector.Warn(err)
+		return nil
+	}
+	for _, l := range s.conn.combinedLogs {
+		l.Println(err)
+	}
+	for _, l := range s.conn.errorLogs {
+		l.Println(err)
+	}
+	return nil
+}
+
+// keyConditions finds the conditions of the Scope that compare the primary
+// key or a foreign key to a single value
+func (q *queryable) keyConditions() ([]string, []interface{}) {
+	var columns []string
+	var values []interface{}
+	for _, cond := range q.conditions {
+		var column string
+		var val interface{}
+		switch c := cond.(type) {
+		case *equalCondition:
+			column, val = c.column, c.val
+		case *varyCondition:
+			if c.cond != EQUAL {
+				continue
+			}
+			column, val = c.column, c.val
+		default:
+			continue
+		}
+		if isNil(val) {
+			continue
+		}
+		if i := strings.LastIndex(column, "."); i >= 0 {
+			column = column[i+1:]
+		}
+		if q.source.isKeyColumn(column) {
+			columns = append(columns, q.source.SqlName+"."+column)
+			values = append(values, val)
+		}
+	}
+	return columns, values
+}
+
+func (s *source) isKeyColumn(column string) bool {
+	if s.ID != nil && s.ID.ColumnInfo != nil && s.ID.SqlColumn == column {
+		return true
+	}
+	for _, f := range s.Fields {
+		if f.IsForeignKey && f.ColumnInfo != nil && f.SqlColumn == column {
+			return true
+		}
+	}
+	return false
+}
+
+// relationFor finds the relation of another Mapper that could load the
+// records of this Mapper, a belongs to relation when the records were
+// looked up by primary key, or a has many relation for a foreign key
+func (s *source) relationFor(columns []string) string {
+	byID := s.ID != nil && s.ID.ColumnInfo != nil && columns[0] == s.SqlName+"."+s.ID.SqlColumn
+	// the Mappers are checked by name so the same relation is suggested
+	// every time when more than one could have loaded the records
+	others := make([]*source, 0, len(s.conn.mappedStructs))
+	for _, other := range s.conn.mappedStructs {
+		others = append(others, other)
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i].Name < others[j].Name })
+	for _, other := range others {
+		for _, r := range other.relations {
+			if r.Relation == nil || r.Relation.FullName != s.FullName {
+				continue
+			}
+			if byID == (r.Kind != reflect.Slice) {
+				return other.Name + "." + r.structOptions.Name
+			}
+		}
+	}
+	return ""
+}
+
+// the directory of the db package, frames in it are skipped when looking
+// for the call site, other than the tests for db
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// callSite returns the file and line of the first caller outside of db
+func callSite() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if filepath.Dir(frame.File) != packageDir || strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
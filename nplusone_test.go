@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/acsellers/assert"
+)
+
+func TestNPlusOne(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+			Users := c.m("User")
+			c.NPlusOne = &NPlusOneDetector{Threshold: 1, Fail: true}
+
+			test.Section("Finding by primary key")
+			ctx := UnitOfWork(context.Background())
+			var u user
+			Users.WithContext(ctx).Find(1, &u)
+			e := Users.WithContext(ctx).Find(2, &u)
+			npe, ok := e.(*NPlusOneError)
+			test.IsTrue(ok)
+			if ok {
+				test.AreEqual("User", npe.Mapper)
+				test.AreEqual("Find", npe.Method)
+				test.AreEqual([]string{"users.id"}, npe.Columns)
+				test.AreEqual("Post.User", npe.Relation)
+				test.IsTrue(strings.Contains(npe.CallSite, "nplusone_test.go"))
+			}
+
+			test.Section("Finding by foreign key")
+			var posts []post
+			test.NoError(Posts.WithContext(ctx).EqualTo("user_id", 1).RetrieveAll(&posts))
+			e = Posts.WithContext(ctx).EqualTo("user_id", 2).RetrieveAll(&posts)
+			npe, ok = e.(*NPlusOneError)
+			test.IsTrue(ok)
+			if ok {
+				test.AreEqual("User.Post", npe.Relation)
+			}
+
+			test.Section("Same key and other units of work")
+			test.NoError(Posts.WithContext(ctx).EqualTo("user_id", 1).RetrieveAll(&posts))
+			test.NoError(Posts.WithContext(UnitOfWork(context.Background())).EqualTo("user_id", 2).RetrieveAll(&posts))
+			test.NoError(Posts.EqualTo("user_id", 2).RetrieveAll(&posts))
+
+			test.Section("Warning")
+			var warnings []*NPlusOneError
+			c.NPlusOne = &NPlusOneDetector{
+				Threshold: 1,
+				Warn:      func(err *NPlusOneError) { warnings = append(warnings, err) },
+			}
+			ctx = UnitOfWork(context.Background())
+			for i := 1; i <= 3; i++ {
+				test.NoError(Posts.WithContext(ctx).EqualTo("user_id", i).RetrieveAll(&posts))
+			}
+			test.AreEqual(1, len(warnings))
+
+			c.NPlusOne = nil
+		}
+	})
+}
+
+func TestNPlusOneRelation(t *testing.T) {
+	Within(t, func(test *Test) {
+		test.Section("Several relations to the Mapper")
+		conn := &Connection{mappedStructs: make(map[string]*source)}
+		posts := &source{Name: "Post", FullName: "db:post", SqlName: "posts", conn: conn}
+		for _, name := range []string{"Writer", "Editor", "Reviewer"} {
+			conn.mappedStructs["db:"+strings.ToLower(name)] = &source{
+				Name: name,
+				relations: []*sourceMapping{&sourceMapping{structOptions: &structOptions{
+					Name:     "Posts",
+					Kind:     reflect.Slice,
+					Relation: posts,
+				}}},
+			}
+		}
+		for i := 0; i < 20; i++ {
+			test.AreEqual("Editor.Posts", posts.relationFor([]string{"posts.user_id"}))
+		}
+	})
+}
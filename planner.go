@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 type planner struct {
@@ -25,6 +26,122 @@ func (s *source) mapPlan(v reflector) *planner {
 	return p
 }
 
+// namedPlan maps the columns of a custom selection into the fields of the
+// struct with the same column name, columns without a field are discarded
+func (s *source) namedPlan(v reflector, columns []string) *planner {
+	p := &planner{[]*reflectScanner{}}
+	for _, column := range columns {
+		p.scanners = append(p.scanners, &reflectScanner{parent: v, column: s.fieldFor(column)})
+	}
+
+	return p
+}
+
+// fieldFor finds the field for a selected column, fields that aren't in
+// the table are found using the Config's FieldToColumn
+func (s *source) fieldFor(column string) *sourceMapping {
+	for _, f := range s.Fields {
+		if f.MappedColumn() && f.SqlColumn == column {
+			return f
+		}
+	}
+	for _, f := range s.Fields {
+		if f.structOptions == nil || f.ColumnInfo != nil || s.config.FieldToColumn(s.Name, f.structOptions.Name) != column {
+			continue
+		}
+		switch f.Kind {
+		case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return &sourceMapping{f.structOptions, &ColumnInfo{Name: column, SqlColumn: column, Nullable: true}}
+		}
+	}
+	return nil
+}
+
+// selectPlan maps columns into the fields of any struct, using the Config
+// to name the fields, v is a pointer to the struct. Fields of embedded
+// structs are mapped as well.
+func (q *queryable) selectPlan(v reflect.Value, columns []string) *planner {
+	p := &planner{[]*reflectScanner{}}
+	for _, column := range columns {
+		rs := q.source.config.scannerFor(v, column)
+		if rs == nil {
+			rs = &reflectScanner{parent: reflector{v}}
+		}
+		p.scanners = append(p.scanners, rs)
+	}
+
+	return p
+}
+
+func (c Config) scannerFor(v reflect.Value, column string) *reflectScanner {
+	t := v.Elem().Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if rs := c.scannerFor(v.Elem().Field(i).Addr(), column); rs != nil {
+				return rs
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if c.FieldToColumn(t.Name(), field.Name) != column && !strings.EqualFold(field.Name, column) {
+			continue
+		}
+
+		options := &structOptions{Name: field.Name, Index: i, Kind: field.Type.Kind()}
+		info := &ColumnInfo{Name: column, SqlColumn: column}
+		switch options.Kind {
+		case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			info.Nullable = true
+		}
+		return &reflectScanner{parent: reflector{v}, column: &sourceMapping{options, info}}
+	}
+	return nil
+}
+
+// includePlan is mapPlan for the table of an included relation, the
+// columns are scanned as nullable since outer joins return NULL for the
+// records that are missing
+func (s *source) includePlan(v reflector) *planner {
+	p := s.mapPlan(v)
+	for i, rs := range p.scanners {
+		switch rs.column.Kind {
+		case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			info := *rs.column.ColumnInfo
+			info.Nullable = true
+			p.scanners[i] = &reflectScanner{parent: v, column: &sourceMapping{rs.column.structOptions, &info}}
+		}
+	}
+	return p
+}
+
+// scanned returns whether the nullable column of the field wasn't NULL
+func (p *planner) scanned(sm *sourceMapping) bool {
+	for _, rs := range p.scanners {
+		if rs.column == nil || rs.column.structOptions != sm.structOptions {
+			continue
+		}
+		switch rs.column.Kind {
+		case reflect.String:
+			return rs.s.Valid
+		case reflect.Bool:
+			return rs.b.Valid
+		case reflect.Float32, reflect.Float64:
+			return rs.f.Valid
+		default:
+			return rs.i.Valid
+		}
+	}
+	return false
+}
+
 func (s *source) selectColumns() []string {
 	output := []string{}
 	for _, col := range s.Fields {
@@ -54,7 +171,7 @@ type mixed interface {
 func (p *planner) Finalize(val interface{}) {
 	mx, ok := val.(mixed)
 	for _, s := range p.scanners {
-		if s.column.Nullable {
+		if s.column != nil && s.column.Nullable {
 			if s.finalize() && ok {
 				mx.SetNull(s.column.SqlColumn)
 			}
@@ -71,6 +188,8 @@ type reflectScanner struct {
 	i      sql.NullInt64
 	s      sql.NullString
 	isnull bool
+	// discard holds the values of columns that aren't mapped to a field
+	discard interface{}
 }
 
 type reflector struct {
@@ -78,6 +197,9 @@ type reflector struct {
 }
 
 func (rf *reflectScanner) iface() interface{} {
+	if rf.column == nil {
+		return &rf.discard
+	}
 	if rf.column.Nullable {
 		switch rf.column.Kind {
 		case reflect.String:
@@ -114,6 +236,12 @@ func (rf *reflectScanner) finalize() bool {
 		} else {
 			return true
 		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rf.i.Valid {
+			rf.parent.item.Elem().Field(rf.column.Index).SetUint(uint64(rf.i.Int64))
+		} else {
+			return true
+		}
 	default:
 		if rf.i.Valid {
 			rf.parent.item.Elem().Field(rf.column.Index).SetInt(rf.i.Int64)
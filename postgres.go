@@ -1,11 +1,15 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type postgresDialect struct {
@@ -115,3 +119,94 @@ func (d postgresDialect) Create(mapper Mapper, values map[string]interface{}) (s
 
 	return d.Dialect.FormatQuery(output), sqlVals
 }
+
+// Postgres errors 40001 (serialization_failure) and 40P01 (deadlock_detected)
+// can be retried
+func (d postgresDialect) RetryableError(err error) bool {
+	var pe *pq.Error
+	if errors.As(err, &pe) {
+		return pe.Code == "40001" || pe.Code == "40P01"
+	}
+	return false
+}
+
+// Postgres can't lock the rows of grouped or combined queries, since the
+// rows returned aren't rows of the table
+func (d postgresDialect) Lock(scope Scope) (string, error) {
+	lock := scope.LockSql()
+	if q := scopeOf(scope); lock != "" && q != nil {
+		switch {
+		case q.groupBy != "" || len(q.having) > 0:
+			return "", errors.New("postgres does not allow " + lock + " with GROUP BY")
+		case q.from != nil:
+			if ct, ok := q.from.table.(*compoundTable); ok {
+				return "", errors.New("postgres does not allow " + lock + " with " + ct.op)
+			}
+		}
+	}
+	return lock, nil
+}
+
+// Postgres plans are requested as JSON, so they can be read without
+// parsing the text format
+func (d postgresDialect) Explain(query string, analyze bool) (string, error) {
+	if analyze {
+		return "EXPLAIN (ANALYZE, FORMAT JSON) " + query, nil
+	}
+	return "EXPLAIN (FORMAT JSON) " + query, nil
+}
+
+func (d postgresDialect) ParsePlan(rows *sql.Rows) (*QueryPlan, error) {
+	var doc []byte
+	for rows.Next() {
+		var b []byte
+		if err := rows.Scan(&b); err != nil {
+			return nil, err
+		}
+		doc = append(doc, b...)
+	}
+	return parsePostgresPlan(doc)
+}
+
+type postgresPlan struct {
+	NodeType        string         `json:"Node Type"`
+	RelationName    string         `json:"Relation Name"`
+	IndexName       string         `json:"Index Name"`
+	PlanRows        float64        `json:"Plan Rows"`
+	TotalCost       float64        `json:"Total Cost"`
+	ActualRows      float64        `json:"Actual Rows"`
+	ActualTotalTime float64        `json:"Actual Total Time"`
+	Plans           []postgresPlan `json:"Plans"`
+}
+
+func (pp postgresPlan) node() *PlanNode {
+	pn := &PlanNode{
+		Type:       pp.NodeType,
+		Table:      pp.RelationName,
+		Index:      pp.IndexName,
+		Rows:       pp.PlanRows,
+		Cost:       pp.TotalCost,
+		ActualRows: pp.ActualRows,
+		ActualTime: time.Duration(pp.ActualTotalTime * float64(time.Millisecond)),
+		Detail:     pp.NodeType,
+	}
+	for _, child := range pp.Plans {
+		pn.Children = append(pn.Children, child.node())
+	}
+	return pn
+}
+
+func parsePostgresPlan(doc []byte) (*QueryPlan, error) {
+	var explained []struct {
+		Plan postgresPlan `json:"Plan"`
+	}
+	if err := json.Unmarshal(doc, &explained); err != nil {
+		return nil, err
+	}
+
+	plan := &QueryPlan{}
+	for _, e := range explained {
+		plan.Nodes = append(plan.Nodes, e.Plan.node())
+	}
+	return plan, nil
+}
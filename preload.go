@@ -0,0 +1,339 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// the number of keys sent in each preload query when the Connection
+// doesn't set a PreloadBatchSize
+const defaultPreloadBatch = 500
+
+// A preload is a relation path to load after the records of a Scope are
+// retrieved, using the Scope for the related Mapper when one was given
+type preload struct {
+	path  string
+	scope *queryable
+}
+
+// Preload loads the relations named by the strings with a separate query
+// for each relation once the records are retrieved. Nested relations are
+// named with a path like "Posts.Comments", and a Scope passed after a
+// relation is used to retrieve the records of that relation.
+func (q *queryable) Preload(relations ...interface{}) Scope {
+	nq := q.Identity().(*queryable)
+	for _, r := range relations {
+		switch rv := r.(type) {
+		case string:
+			if _, err := q.source.relationPath(rv); err != nil {
+				nq.err = err
+				return nq
+			}
+			nq.preloads = append(nq.preloads, &preload{path: rv})
+		case Scope:
+			if len(nq.preloads) == 0 {
+				nq.err = errors.New("Preload Scopes must follow the relation they are for")
+				return nq
+			}
+			last := nq.preloads[len(nq.preloads)-1]
+			path, _ := q.source.relationPath(last.path)
+			related := path[len(path)-1].Relation
+			sq := scopeOf(rv)
+			if sq == nil || sq.source.FullName != related.FullName {
+				nq.err = fmt.Errorf("Can't preload %s with a %T", last.path, r)
+				return nq
+			}
+			nq.preloads[len(nq.preloads)-1] = &preload{path: last.path, scope: sq}
+		default:
+			nq.err = fmt.Errorf("Can't preload a %T", r)
+			return nq
+		}
+	}
+	return nq
+}
+
+// recordsOf returns the elements of a slice of records
+func recordsOf(slice reflect.Value) []reflect.Value {
+	records := make([]reflect.Value, slice.Len())
+	for i := range records {
+		records[i] = slice.Index(i)
+	}
+	return records
+}
+
+// relationPath finds the relation for each struct field name in the path
+func (s *source) relationPath(path string) ([]*sourceMapping, error) {
+	var relations []*sourceMapping
+	current := s
+	for _, name := range strings.Split(path, ".") {
+		var found *sourceMapping
+		for _, r := range current.relations {
+			if r.structOptions.Name == name {
+				found = r
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("Couldn't find a relation named %s for %s", name, current.Name)
+		}
+		if found.ForeignKey == nil && found.Through == nil {
+			return nil, fmt.Errorf("Couldn't find a foreign key for %s.%s", current.Name, name)
+		}
+		relations = append(relations, found)
+		current = found.Relation
+	}
+	return relations, nil
+}
+
+// loadPreloads retrieves the preloaded relations for the records, which
+// must be addressable structs of the Scope's Mapper
+func (q *queryable) loadPreloads(records []reflect.Value) error {
+	if len(q.preloads) == 0 || len(records) == 0 {
+		return nil
+	}
+
+	// group the nested paths under the first relation of the path
+	var names []string
+	scopes := make(map[string]*queryable)
+	nested := make(map[string][]*preload)
+	for _, p := range q.preloads {
+		name, rest := p.path, ""
+		if i := strings.Index(p.path, "."); i >= 0 {
+			name, rest = p.path[:i], p.path[i+1:]
+		}
+		if _, ok := nested[name]; !ok {
+			names = append(names, name)
+			nested[name] = []*preload{}
+		}
+		if rest == "" {
+			if p.scope != nil {
+				scopes[name] = p.scope
+			}
+		} else {
+			nested[name] = append(nested[name], &preload{path: rest, scope: p.scope})
+		}
+	}
+
+	for _, name := range names {
+		path, err := q.source.relationPath(name)
+		if err != nil {
+			return err
+		}
+		rq, ok := scopes[name]
+		if !ok {
+			rq = path[0].Relation.Identity().(*queryable)
+		}
+		// the relations are the Mappers of the Connection they were created
+		// on, so they are bound to run on the same transaction as this Scope
+		rq = rq.Identity().(*queryable)
+		rq.source = q.source.conn.bind(rq.source)
+		if rq.ctx == nil {
+			rq.ctx = q.ctx
+		}
+		rq.primary = rq.primary || q.primary
+		rq.preloads = append(rq.preloads, nested[name]...)
+		if err = q.source.preloadRelation(path[0], rq, records); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preloadRelation retrieves the related records for the relation with
+// IN queries on the keys of the records and places them in the relation's
+// field of each record
+func (s *source) preloadRelation(sm *sourceMapping, rq *queryable, records []reflect.Value) error {
+	if sm.Through != nil {
+		return s.preloadThrough(sm, rq, records)
+	}
+	related := sm.Relation
+	var recordKey, relatedKey *sourceMapping
+	if sm.Kind != reflect.Slice && s.hasColumn(sm.ForeignKey) {
+		// belongs to, the records hold the key of the related record
+		recordKey, relatedKey = s.mappingFor(sm.ForeignKey), related.ID
+	} else {
+		recordKey, relatedKey = s.ID, related.mappingFor(sm.ForeignKey)
+	}
+	if recordKey == nil || relatedKey == nil {
+		return fmt.Errorf("Couldn't find a foreign key for %s.%s", s.Name, sm.structOptions.Name)
+	}
+
+	elemType := structOf(records[0].Field(sm.structOptions.Index).Type())
+	loaded, err := s.preloadRecords(elemType, rq, relatedKey, keysOf(records, recordKey))
+	if err != nil {
+		return err
+	}
+	matches := make(map[string][]reflect.Value)
+	for i := 0; i < loaded.Len(); i++ {
+		item := loaded.Index(i)
+		key := fmt.Sprint(item.Field(relatedKey.structOptions.Index).Interface())
+		matches[key] = append(matches[key], item)
+	}
+
+	for _, record := range records {
+		found := matches[fmt.Sprint(record.Field(recordKey.structOptions.Index).Interface())]
+		placeRelated(record.Field(sm.structOptions.Index), found)
+	}
+	return nil
+}
+
+// preloadThrough preloads a has and belongs to many relation, the pairs of
+// keys are read from the join table and then the related records by key
+func (s *source) preloadThrough(sm *sourceMapping, rq *queryable, records []reflect.Value) error {
+	jt := sm.Through
+	keys := keysOf(records, s.ID)
+	related := make(map[string][]string)
+	var relatedKeys []interface{}
+	seen := make(map[string]bool)
+	for start := 0; start < len(keys); start += s.preloadBatch() {
+		end := start + s.preloadBatch()
+		if end > len(keys) {
+			end = len(keys)
+		}
+		holders := make([]string, end-start)
+		for i := range holders {
+			holders[i] = "?"
+		}
+		query := "SELECT " + jt.Key + ", " + jt.ForeignKey + " FROM " + jt.Table +
+			" WHERE " + jt.Key + " IN (" + strings.Join(holders, ", ") + ")"
+		rows, err := s.runRead(rq.runContext("Preload"), rq.primary, s.conn.Dialect.FormatQuery(query), keys[start:end])
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var key, fk interface{}
+			if err = rows.Scan(&key, &fk); err != nil {
+				rows.Close()
+				return err
+			}
+			ks, fks := keyString(key), keyString(fk)
+			related[ks] = append(related[ks], fks)
+			if !seen[fks] {
+				seen[fks] = true
+				relatedKeys = append(relatedKeys, fk)
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	elemType := structOf(records[0].Field(sm.structOptions.Index).Type())
+	loaded, err := s.preloadRecords(elemType, rq, sm.Relation.ID, relatedKeys)
+	if err != nil {
+		return err
+	}
+	byKey := make(map[string]reflect.Value)
+	for i := 0; i < loaded.Len(); i++ {
+		item := loaded.Index(i)
+		byKey[fmt.Sprint(item.Field(sm.Relation.ID.structOptions.Index).Interface())] = item
+	}
+
+	for _, record := range records {
+		var found []reflect.Value
+		for _, fk := range related[fmt.Sprint(record.Field(s.ID.structOptions.Index).Interface())] {
+			if item, ok := byKey[fk]; ok {
+				found = append(found, item)
+			}
+		}
+		placeRelated(record.Field(sm.structOptions.Index), found)
+	}
+	return nil
+}
+
+// keyString is fmt.Sprint for a key scanned into an interface{}, some
+// drivers return the text of the number as bytes
+func keyString(key interface{}) string {
+	if b, ok := key.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(key)
+}
+
+func (s *source) preloadBatch() int {
+	if s.conn.PreloadBatchSize <= 0 {
+		return defaultPreloadBatch
+	}
+	return s.conn.PreloadBatchSize
+}
+
+// keysOf returns the distinct values of the field that aren't zero
+func keysOf(records []reflect.Value, field *sourceMapping) []interface{} {
+	var keys []interface{}
+	seen := make(map[string]bool)
+	for _, record := range records {
+		kv := record.Field(field.structOptions.Index)
+		if reflect.Zero(kv.Type()).Interface() == kv.Interface() {
+			continue
+		}
+		key := fmt.Sprint(kv.Interface())
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, kv.Interface())
+		}
+	}
+	return keys
+}
+
+// preloadRecords retrieves the related records of the relation with the
+// Scope, in batches of keys for the related key column
+func (s *source) preloadRecords(elemType reflect.Type, rq *queryable, relatedKey *sourceMapping, keys []interface{}) (reflect.Value, error) {
+	loaded := reflect.New(reflect.SliceOf(elemType)).Elem()
+	for start := 0; start < len(keys); start += s.preloadBatch() {
+		end := start + s.preloadBatch()
+		if end > len(keys) {
+			end = len(keys)
+		}
+		column := rq.tableAlias() + "." + relatedKey.SqlColumn
+		part := reflect.New(reflect.SliceOf(elemType))
+		if err := rq.In(column, keys[start:end]).RetrieveAll(part.Interface()); err != nil {
+			return loaded, err
+		}
+		loaded = reflect.AppendSlice(loaded, part.Elem())
+	}
+	return loaded, nil
+}
+
+// placeRelated sets the relation field to the related records found for
+// the record
+func placeRelated(field reflect.Value, found []reflect.Value) {
+	fieldType := field.Type()
+	switch {
+	case fieldType.Kind() == reflect.Slice:
+		items := reflect.MakeSlice(fieldType, 0, len(found))
+		for _, item := range found {
+			if fieldType.Elem().Kind() == reflect.Ptr {
+				item = item.Addr()
+			}
+			items = reflect.Append(items, item)
+		}
+		field.Set(items)
+	case len(found) == 0:
+		field.Set(reflect.Zero(fieldType))
+	case fieldType.Kind() == reflect.Ptr:
+		field.Set(found[0].Addr())
+	default:
+		field.Set(found[0])
+	}
+}
+
+// structOf is the struct type held by a relation field
+func structOf(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Slice || t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// mappingFor returns the field mapped to the column
+func (s *source) mappingFor(ci *ColumnInfo) *sourceMapping {
+	for _, f := range s.Fields {
+		if f.ColumnInfo == ci && f.structOptions != nil {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/acsellers/assert"
+)
+
+func TestPreload(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+			Users := c.m("User")
+
+			test.Section("Has many")
+			var users []user
+			test.NoError(Users.Preload("Post").RetrieveAll(&users))
+			test.AreEqual(1, len(users))
+			if len(users) == 1 {
+				test.AreEqual(1, len(users[0].Post))
+				if len(users[0].Post) == 1 {
+					test.AreEqual("First Post", users[0].Post[0].Title)
+				}
+			}
+
+			test.Section("Belongs to")
+			var posts []post
+			test.NoError(Posts.Order("id").Preload("User").RetrieveAll(&posts))
+			test.AreEqual(2, len(posts))
+			if len(posts) == 2 {
+				test.AreEqual("wat", posts[0].User.Name)
+				test.AreEqual(0, posts[1].User.Id)
+			}
+			var p post
+			test.NoError(Posts.Preload("User").Find(1, &p))
+			test.AreEqual("wat", p.User.Name)
+
+			test.Section("Nested and scoped")
+			users = nil
+			test.NoError(Users.Preload("Post", "Post.User").RetrieveAll(&users))
+			if len(users) == 1 && len(users[0].Post) == 1 {
+				test.AreEqual("wat", users[0].Post[0].User.Name)
+			}
+			users = nil
+			test.NoError(Users.Preload("Post", Posts.EqualTo("title", "banana")).RetrieveAll(&users))
+			if len(users) == 1 {
+				test.AreEqual(0, len(users[0].Post))
+			}
+
+			test.Section("Batches")
+			test.NoError(Posts.EqualTo("id", 2).UpdateAttribute("user_id", 2))
+			var statements int
+			c.AddAfterHook(func(st *Statement, result sql.Result, err error, d time.Duration) {
+				statements++
+			})
+			c.PreloadBatchSize = 1
+			posts = nil
+			test.NoError(Posts.Order("id").Preload("User").RetrieveAll(&posts))
+			test.AreEqual(3, statements)
+			if len(posts) == 2 {
+				test.AreEqual("wat", posts[0].User.Name)
+			}
+			c.PreloadBatchSize = 0
+			c.afterHooks = nil
+			test.NoError(Posts.EqualTo("id", 2).UpdateAttribute("user_id", nil))
+
+			test.Section("Transaction")
+			test.AreEqual("rollback", c.Transaction(func(tx *Connection) error {
+				test.NoError(tx.Mapper(Posts).SaveAll(&post{
+					Title:     "Uncommitted",
+					Permalink: "uncommitted",
+					Body:      "Only in the transaction",
+					UserId:    1,
+				}))
+				users = nil
+				test.NoError(tx.Mapper(Users).Preload("Post").RetrieveAll(&users))
+				if len(users) == 1 {
+					test.AreEqual(2, len(users[0].Post))
+				}
+				users = nil
+				scoped := tx.Mapper(Posts).EqualTo("title", "Uncommitted")
+				test.NoError(tx.Mapper(Users).Preload("Post", scoped).RetrieveAll(&users))
+				if len(users) == 1 {
+					test.AreEqual(1, len(users[0].Post))
+				}
+				return errors.New("rollback")
+			}).Error())
+
+			test.Section("Errors")
+			test.IsNotNil(Posts.Preload("Editor").RetrieveAll(&posts))
+			test.IsNotNil(Posts.Preload("User", Posts).RetrieveAll(&posts))
+			test.IsNotNil(Posts.Preload(Users).RetrieveAll(&posts))
+		}
+	})
+}
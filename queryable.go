@@ -1,10 +1,13 @@
 package db
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"reflect"
 	"strings"
 	"time"
+	"unicode"
 )
 
 type COND int
@@ -44,31 +47,90 @@ type Attributes map[string]interface{}
 
 type queryable struct {
 	*source
+	ctx        context.Context
+	primary    bool
 	order      []string
+	orderVals  []interface{}
 	groupBy    string
 	having     []whereCondition
 	offset     int
 	limit      int
 	selection  []selector
 	joins      []*join
+	preloads   []*preload
+	includes   []*includeHop
 	conditions []condition
+	from       *fromTable
+	with       []*commonTable
+	lock       *rowLock
+	// err is an error from building the Scope, it is returned by
+	// the terminal functions instead of running a query
+	err error
 }
 
-func (q *queryable) SelectorSql() string {
+func (q *queryable) SelectorSql() (string, []interface{}) {
 	if len(q.selection) == 0 {
-		return strings.Join(q.source.selectColumns(), ", ")
-	} else {
-		selections := make([]string, len(q.selection))
-		for i, selection := range q.selection {
-			selections[i] = selection.String()
+		columns := q.source.selectColumns()
+		if alias := q.tableAlias(); alias != q.source.SqlName {
+			for i, column := range columns {
+				columns[i] = alias + strings.TrimPrefix(column, q.source.SqlName)
+			}
 		}
-		return strings.Join(selections, ", ")
+		columns = append(columns, q.includeColumns()...)
+		return strings.Join(columns, ", "), []interface{}{}
 	}
-	output := make([]string, len(q.selection))
+	selections := make([]string, len(q.selection))
+	vals := []interface{}{}
 	for i, selection := range q.selection {
-		output[i] = selection.String()
+		selections[i] = selection.String()
+		vals = append(vals, selection.Args...)
 	}
-	return strings.Join(output, ", ")
+	return strings.Join(selections, ", "), vals
+}
+
+// plan maps the columns selected by the Scope into a struct
+func (q *queryable) plan(v reflector) *planner {
+	if len(q.selection) == 0 {
+		return q.source.mapPlan(v)
+	}
+	return q.source.namedPlan(v, q.selectedColumns())
+}
+
+// selectedColumns are the names of the columns of a custom selection as
+// the database will return them, blank for formulas without an alias
+func (q *queryable) selectedColumns() []string {
+	columns := make([]string, len(q.selection))
+	for i, sel := range q.selection {
+		switch {
+		case sel.Alias != "":
+			columns[i] = sel.Alias
+		case sel.Type == singleColumn:
+			columns[i] = sel.Column
+		default:
+			name := sel.Formula[strings.LastIndex(sel.Formula, ".")+1:]
+			if isIdentifier(name) {
+				columns[i] = name
+			}
+		}
+	}
+	return columns
+}
+
+// tableAlias is the name that the columns of the Scope's table are
+// selected with, which is the table name unless From gave it an alias
+func (q *queryable) tableAlias() string {
+	if q.from != nil {
+		return q.from.alias
+	}
+	return q.source.SqlName
+}
+
+// FromSql is the table or derived table that the Scope selects from
+func (q *queryable) FromSql() (string, []interface{}) {
+	if q.from == nil {
+		return q.source.SqlName, []interface{}{}
+	}
+	return q.from.Fragment(), q.from.Values()
 }
 
 func (q *queryable) ConditionSql() (string, []interface{}) {
@@ -105,22 +167,21 @@ func (q *queryable) cleanValues(vals []interface{}) []interface{} {
 	return output
 }
 
-func (q *queryable) JoinsSql() string {
-	if len(q.selection) == 0 {
-		return ""
+func (q *queryable) JoinsSql() (string, []interface{}) {
+	output := ""
+	vals := []interface{}{}
+	for _, join := range q.joins {
+		output += " " + join.Fragment()
+		vals = append(vals, join.Values()...)
 	}
-	output := make([]string, len(q.joins))
-	for i, join := range q.joins {
-		output[i] = join.String()
-	}
-	return strings.Join(output, " ")
+	return output, vals
 }
 
 func (queryable *queryable) EndingSql() (string, []interface{}) {
 	var output string
 	var vals []interface{}
 	if queryable.groupBy != "" {
-		output += " GROUP BY " + queryable.groupBy
+		output += " GROUP BY " + queryable.groupBySql()
 	}
 	if len(queryable.having) > 0 {
 		clauses := []string{}
@@ -128,10 +189,11 @@ func (queryable *queryable) EndingSql() (string, []interface{}) {
 			vals = append(vals, h.Values()...)
 			clauses = append(clauses, h.Fragment())
 		}
-		output += strings.Join(clauses, " AND ")
+		output += " HAVING " + strings.Join(clauses, " AND ")
 	}
 	if len(queryable.order) > 0 {
 		output += " ORDER BY " + strings.Join(queryable.order, ", ")
+		vals = append(vals, queryable.orderVals...)
 	}
 	if queryable.limit != 0 {
 		output += " LIMIT " + fmt.Sprint(queryable.limit)
@@ -143,23 +205,304 @@ func (queryable *queryable) EndingSql() (string, []interface{}) {
 	return output, vals
 }
 
+// groupBySql is the list of columns to GROUP BY, when the Dialect needs
+// every selected column that isn't aggregated to be in the list, they are
+// added after the columns passed to GroupBy
+func (q *queryable) groupBySql() string {
+	if !q.source.conn.Dialect.ExpandGroupBy() {
+		return q.groupBy
+	}
+
+	items := splitColumns(q.groupBy)
+	selection, _ := q.SelectorSql()
+	for _, column := range splitColumns(selection) {
+		if i := strings.LastIndex(strings.ToUpper(column), " AS "); i >= 0 {
+			column = strings.TrimSpace(column[:i])
+		}
+		// formulas, literals and whole tables aren't grouped
+		name := column[strings.LastIndex(column, ".")+1:]
+		if !isIdentifier(name) || unicode.IsDigit(rune(name[0])) {
+			continue
+		}
+		found := false
+		for _, item := range items {
+			if strings.EqualFold(q.aggregateColumn(item), q.aggregateColumn(column)) {
+				found = true
+			}
+		}
+		if !found {
+			items = append(items, column)
+		}
+	}
+	return strings.Join(items, ", ")
+}
+
+// splitColumns splits a list of columns on the commas that aren't inside
+// of parentheses or quotes
+func splitColumns(list string) []string {
+	var columns []string
+	depth, start := 0, 0
+	quoted := false
+	for i, r := range list {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case quoted:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			columns = append(columns, strings.TrimSpace(list[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(list[start:]); last != "" {
+		columns = append(columns, last)
+	}
+	return columns
+}
+
 // Identity is the way to clone a queryable, it is used everywhere
 func (q *queryable) Identity() Scope {
+	// the slices are copied so that appending to the clone can't change
+	// the Scope it was cloned from
 	return &queryable{
 		source:     q.source,
-		order:      q.order,
+		ctx:        q.ctx,
+		primary:    q.primary,
+		order:      append([]string(nil), q.order...),
+		orderVals:  append([]interface{}(nil), q.orderVals...),
+		groupBy:    q.groupBy,
+		having:     append([]whereCondition(nil), q.having...),
 		offset:     q.offset,
 		limit:      q.limit,
-		conditions: q.conditions,
+		selection:  append([]selector(nil), q.selection...),
+		joins:      append([]*join(nil), q.joins...),
+		preloads:   append([]*preload(nil), q.preloads...),
+		includes:   append([]*includeHop(nil), q.includes...),
+		conditions: append([]condition(nil), q.conditions...),
+		from:       q.from,
+		with:       append([]*commonTable(nil), q.with...),
+		lock:       q.lock,
+		err:        q.err,
 	}
 }
 
+// Merge combines the Scope with another Scope for the same Mapper. The
+// conditions of both are used, the orderings of the other Scope are added
+// after these, and the limit, offset, group by, having and selection of the
+// other Scope replace these when they're set.
+func (q *queryable) Merge(scope Scope) (Scope, error) {
+	other, err := q.combinable(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	nq := q.Identity().(*queryable)
+	if other.ctx != nil {
+		nq.ctx = other.ctx
+	}
+	nq.primary = nq.primary || other.primary
+	nq.order = append(nq.order, other.order...)
+	nq.orderVals = append(nq.orderVals, other.orderVals...)
+	if other.groupBy != "" {
+		nq.groupBy = other.groupBy
+	}
+	if len(other.having) > 0 {
+		nq.having = append([]whereCondition(nil), other.having...)
+	}
+	if other.offset != 0 {
+		nq.offset = other.offset
+	}
+	if other.limit != 0 {
+		nq.limit = other.limit
+	}
+	if len(other.selection) > 0 {
+		nq.selection = append([]selector(nil), other.selection...)
+	}
+	if other.from != nil {
+		nq.from = other.from
+	}
+	nq.addCommonTables(other.with)
+	if other.lock != nil {
+		nq.lock = other.lock
+	}
+	nq.addJoins(other.joins)
+	nq.addIncludeHops(other.includes)
+	nq.preloads = append(nq.preloads, other.preloads...)
+	nq.conditions = append(nq.conditions, other.conditions...)
+	return nq, nil
+}
+
+func (q *queryable) WithContext(ctx context.Context) Scope {
+	nq := q.Identity().(*queryable)
+	nq.ctx = ctx
+	return nq
+}
+
+func (q *queryable) OnPrimary() Scope {
+	nq := q.Identity().(*queryable)
+	nq.primary = true
+	return nq
+}
+
+// runContext is the context that the queries for the scope will be
+// run with, by default this is context.Background(). The context also
+// records the Mapper and the terminal method running the queries.
+func (q *queryable) runContext(method string) context.Context {
+	ctx := q.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return withOrigin(ctx, &origin{source: q.source, method: method, scope: q})
+}
+
+// An origin is the Mapper and terminal method (RetrieveAll, Count, ...)
+// that caused a statement to be run, along with the Scope when there is one
+type origin struct {
+	source *source
+	method string
+	scope  *queryable
+}
+
+type originKey struct{}
+
+// withOrigin records the origin of the statements run with ctx, when ctx
+// already has an origin it is kept, so a Find that runs a Retrieve is
+// still a Find.
+func withOrigin(ctx context.Context, o *origin) context.Context {
+	if originOf(ctx) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, originKey{}, o)
+}
+
+// originOf returns the origin recorded in ctx, or nil for statements
+// that were run directly on the Connection
+func originOf(ctx context.Context) *origin {
+	o, _ := ctx.Value(originKey{}).(*origin)
+	return o
+}
+
 func (q *queryable) Where(fragment string, args ...interface{}) Scope {
 	nq := q.Identity().(*queryable)
 	nq.conditions = append(nq.conditions, &whereCondition{fragment, args})
+	nq.useSubqueries(args...)
+	return nq
+}
+
+func (q *queryable) Or(scopes ...Scope) Scope {
+	// a Scope without conditions matches every record, so it still matches
+	// every record when combined with other Scopes
+	return q.orWith(len(q.conditions) == 0, scopes)
+}
+
+// orWith combines the conditions of the Scope and the Scopes passed, a
+// Scope without conditions matches every record unless it is the Scope of
+// a Mapper, which only combines the Scopes passed
+func (q *queryable) orWith(matchesAll bool, scopes []Scope) Scope {
+	nq := q.Identity().(*queryable)
+	var ors []condition
+	if len(nq.conditions) > 0 {
+		ors = append(ors, &andCondition{nq.conditions})
+	}
+
+	for _, scope := range scopes {
+		other, err := q.combinable(scope)
+		if err != nil {
+			nq.err = err
+			return nq
+		}
+		nq.addJoins(other.joins)
+		if len(other.conditions) == 0 {
+			matchesAll = true
+			continue
+		}
+		ors = append(ors, &andCondition{other.conditions})
+	}
+
+	switch {
+	case matchesAll:
+		nq.conditions = nil
+	case len(ors) == 1:
+		nq.conditions = []condition{ors[0]}
+	case len(ors) > 1:
+		nq.conditions = []condition{&orCondition{ors}}
+	}
+	return nq
+}
+
+func (q *queryable) Not(scope Scope) Scope {
+	nq := q.Identity().(*queryable)
+	other, err := q.combinable(scope)
+	if err != nil {
+		nq.err = err
+		return nq
+	}
+
+	nq.addJoins(other.joins)
+	if len(other.conditions) > 0 {
+		nq.conditions = append(nq.conditions, &notCondition{&andCondition{other.conditions}})
+	}
 	return nq
 }
 
+// combinable returns the queryable for a Scope that is going to be
+// combined with this one, which must be for the same Mapper
+func (q *queryable) combinable(scope Scope) (*queryable, error) {
+	other := scopeOf(scope)
+	if other == nil {
+		return nil, fmt.Errorf("Can't combine a %T with a Scope", scope)
+	}
+	if other.source.FullName != q.source.FullName {
+		return nil, fmt.Errorf(
+			"Can't combine a Scope for %s with a Scope for %s",
+			q.source.Name, other.source.Name,
+		)
+	}
+	if other.err != nil {
+		return nil, other.err
+	}
+	return other, nil
+}
+
+// addJoins adds the joins that aren't already in the Scope
+func (q *queryable) addJoins(joins []*join) {
+	for _, j := range joins {
+		found := false
+		for _, existing := range q.joins {
+			if existing.String() == j.String() {
+				found = true
+			}
+		}
+		if !found {
+			q.joins = append(q.joins, j)
+		}
+	}
+}
+
+// scopeOf returns the queryable behind a Scope, MapperPlus and structs
+// that embed them are turned into Scopes with Identity
+func scopeOf(scope Scope) *queryable {
+	switch st := scope.(type) {
+	case *queryable:
+		return st
+	case *mapperPlus:
+		return scopeOf(st.identity().query)
+	case nil:
+		return nil
+	}
+
+	switch st := scope.Identity().(type) {
+	case *queryable:
+		return st
+	case *mapperPlus:
+		return scopeOf(st)
+	}
+	return nil
+}
+
 func (q *queryable) Having(fragment string, args ...interface{}) Scope {
 	nq := q.Identity().(*queryable)
 	nq.having = append(nq.having, whereCondition{fragment, args})
@@ -175,6 +518,7 @@ func (q *queryable) GroupBy(groupItem string) Scope {
 func (q *queryable) EqualTo(column string, val interface{}) Scope {
 	nq := q.Identity().(*queryable)
 	nq.conditions = append(nq.conditions, &equalCondition{column, val})
+	nq.useSubqueries(val)
 	return nq
 }
 
@@ -187,12 +531,14 @@ func (q *queryable) Between(column string, lower, upper interface{}) Scope {
 func (q *queryable) In(column string, items interface{}) Scope {
 	nq := q.Identity().(*queryable)
 	nq.conditions = append(nq.conditions, newInCondition(column, items))
+	nq.useSubqueries(items)
 	return nq
 }
 
 func (q *queryable) Cond(column string, condition COND, val interface{}) Scope {
 	nq := q.Identity().(*queryable)
 	nq.conditions = append(nq.conditions, &varyCondition{column, condition, val})
+	nq.useSubqueries(val)
 
 	return nq
 }
@@ -215,116 +561,200 @@ func (q *queryable) OrderBy(column, direction string) Scope {
 	return nq
 }
 
-func (q *queryable) Order(ordering string) Scope {
+func (q *queryable) Order(ordering interface{}) Scope {
 	nq := q.Identity().(*queryable)
-	if !(strings.HasSuffix(ordering, "DESC") || strings.HasSuffix(ordering, "ASC")) {
-		ordering = ordering + " ASC"
+	var fragment string
+	switch ov := ordering.(type) {
+	case string:
+		fragment = ov
+	case SqlBit:
+		fragment = ov.Fragment()
+		nq.orderVals = append(nq.orderVals, ov.Values()...)
+	default:
+		nq.err = fmt.Errorf("Can't order by a %T", ordering)
+		return nq
 	}
-	nq.order = append(nq.order, ordering)
+	if !(strings.HasSuffix(fragment, "DESC") || strings.HasSuffix(fragment, "ASC")) {
+		fragment = fragment + " ASC"
+	}
+	nq.order = append(nq.order, fragment)
 	return nq
 }
 
-func (q *queryable) Reorder(ordering string) Scope {
+func (q *queryable) Reorder(ordering interface{}) Scope {
 	nq := q.Identity().(*queryable)
 	nq.order = []string{}
+	nq.orderVals = nil
 	return nq.Order(ordering)
 }
 
 func (q *queryable) Count() (int64, error) {
-	ct := "COUNT(" + q.source.SqlName + "." + q.source.ID.SqlColumn + ")"
+	if err := q.readErr(); err != nil {
+		return 0, err
+	}
+	ct := "COUNT(" + q.tableAlias() + "." + q.source.ID.SqlColumn + ")"
+	if len(q.joins) > 0 {
+		// joins to has many relations return a row for each related record
+		ct = "COUNT(DISTINCT " + q.tableAlias() + "." + q.source.ID.SqlColumn + ")"
+	}
 	qq := q.Identity().(*queryable)
 	qq.selection = []selector{selector{Formula: ct}}
+	qq.lock = nil
+	if q.countsRows() {
+		qq = &queryable{
+			source:    q.source,
+			ctx:       q.ctx,
+			primary:   q.primary,
+			selection: []selector{selector{Formula: "COUNT(*)"}},
+			from:      &fromTable{q.countedScope(), "counted"},
+		}
+	}
 
 	var count int64
 	query, values := qq.source.conn.Dialect.Query(qq)
-	row := qq.source.runQueryRow(query, values)
+	row := qq.source.runReadRow(qq.runContext("Count"), qq.primary, query, values)
 	err := row.Scan(&count)
-	if err != nil {
-		fmt.Println(query)
+
+	return count, err
+}
+
+// countsRows is whether the Scope needs to be counted by wrapping it in a
+// subquery, since grouping, limiting or a DISTINCT selection changes the
+// rows that are returned
+func (q *queryable) countsRows() bool {
+	if q.groupBy != "" || q.limit != 0 || q.offset != 0 {
+		return true
 	}
+	for _, sel := range q.selection {
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(sel.String())), "DISTINCT ") {
+			return true
+		}
+	}
+	return false
+}
+
+// countedScope is the Scope that is wrapped by Count, it selects only the
+// columns needed to return the same rows
+func (q *queryable) countedScope() *queryable {
+	cq := q.Identity().(*queryable)
+	cq.lock = nil
+	if cq.limit == 0 && cq.offset == 0 {
+		cq.order = nil
+	}
+	switch {
+	case cq.groupBy != "":
+		cq.selection = []selector{selector{Formula: cq.groupBy}}
+	case len(cq.selection) == 0:
+		cq.selection = []selector{selector{Formula: cq.tableAlias() + "." + cq.source.ID.SqlColumn}}
+	}
+	return cq
+}
+
+/*
+Exists returns whether any records match the Scope, it is a cheaper way to
+check than Count since the database can stop looking after it finds a
+record.
+
+  if found, e := Users.EqualTo("email", email).Exists(); found {
+    return errors.New("email is already taken")
+  }
+*/
+func (q *queryable) Exists() (bool, error) {
+	if err := q.readErr(); err != nil {
+		return false, err
+	}
+	qq := q.Identity().(*queryable)
+	qq.selection = []selector{selector{Formula: "1"}}
+	qq.order = nil
+	qq.lock = nil
+	qq.limit = 1
+
+	var found int
+	query, values := qq.source.conn.Dialect.Query(qq)
+	row := qq.source.runReadRow(qq.runContext("Exists"), qq.primary, query, values)
+	err := row.Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (q *queryable) CountOn(column string) (int64, error) {
+	if err := q.readErr(); err != nil {
+		return 0, err
+	}
+	qq := q.Identity().(*queryable)
+	qq.selection = []selector{selector{Formula: "COUNT(" + column + ")"}}
+	qq.lock = nil
+
+	var count int64
+	query, values := qq.source.conn.Dialect.Query(qq)
+	row := qq.source.runReadRow(qq.runContext("CountOn"), qq.primary, query, values)
+	err := row.Scan(&count)
 
 	return count, err
 }
 
 func (q *queryable) UpdateAttribute(column string, val interface{}) error {
+	if q.err != nil {
+		return q.err
+	}
 	query, vals := q.source.conn.Dialect.Update(q, map[string]interface{}{column: val})
-	_, err := q.source.runExec(query, vals)
+	_, err := q.source.runUpdate(q.runContext("UpdateAttribute"), query, vals)
 
 	return err
 }
 func (q *queryable) UpdateAttributes(values Attributes) error {
+	if q.err != nil {
+		return q.err
+	}
 	query, vals := q.source.conn.Dialect.Update(q, values)
-	_, err := q.source.runExec(query, vals)
+	_, err := q.source.runUpdate(q.runContext("UpdateAttributes"), query, vals)
 	return err
 }
 func (q *queryable) UpdateSql(sql string, vals ...interface{}) error {
 	panic("UNIMPLEMENTED")
 }
 func (q *queryable) Delete() error {
+	if q.err != nil {
+		return q.err
+	}
 	query, vals := q.source.conn.Dialect.Delete(q)
-	_, err := q.source.runExec(query, vals)
+	_, err := q.source.runUpdate(q.runContext("Delete"), query, vals)
 	return err
 }
 func (q *queryable) LeftJoin(joins ...interface{}) Scope {
-	nq := q.Identity().(*queryable)
-	for _, j := range joins {
-		jn, cd := newJoin("LEFT", j, q)
-		nq.joins = append(nq.joins, jn...)
-		if len(cd) > 0 {
-			nq.conditions = append(nq.conditions, cd...)
-		}
-	}
-	return nq
+	return q.joinWith("LEFT", joins)
 }
 func (q *queryable) InnerJoin(joins ...interface{}) Scope {
-	nq := q.Identity().(*queryable)
-	for _, j := range joins {
-		jn, cd := newJoin("INNER", j, q)
-		nq.joins = append(nq.joins, jn...)
-		if len(cd) > 0 {
-			nq.conditions = append(nq.conditions, cd...)
-		}
-	}
-	return nq
+	return q.joinWith("INNER", joins)
 }
 func (q *queryable) FullJoin(joins ...interface{}) Scope {
-	nq := q.Identity().(*queryable)
-	for _, j := range joins {
-		jn, cd := newJoin("FULL OUTER", j, q)
-		nq.joins = append(nq.joins, jn...)
-		if len(cd) > 0 {
-			nq.conditions = append(nq.conditions, cd...)
-		}
-	}
-	return nq
+	return q.joinWith("FULL OUTER", joins)
 }
 func (q *queryable) RightJoin(joins ...interface{}) Scope {
+	return q.joinWith("RIGHT OUTER", joins)
+}
+
+// joinWith adds the joins for each descriptor, later descriptors may join
+// from the tables joined by earlier ones
+func (q *queryable) joinWith(Type string, joins []interface{}) Scope {
 	nq := q.Identity().(*queryable)
 	for _, j := range joins {
-		jn, cd := newJoin("RIGHT OUTER", j, q)
-		nq.joins = append(nq.joins, jn...)
-		if len(cd) > 0 {
-			nq.conditions = append(nq.conditions, cd...)
+		jn, cd, err := newJoin(Type, j, nq)
+		if err != nil {
+			nq.err = err
+			return nq
 		}
+		nq.addJoins(jn)
+		nq.conditions = append(nq.conditions, cd...)
 	}
 	return nq
 }
+
 func (q *queryable) JoinSql(sql string, args ...interface{}) Scope {
-	return q.Identity().JoinSql(sql, args...)
+	nq := q.Identity().(*queryable)
+	nq.joins = append(nq.joins, &join{Compiled: sql, Args: args})
+	return nq
 }
 
-func (q *queryable) LeftInclude(include ...interface{}) Scope {
-	return q.Identity().LeftInclude(include...)
-}
-func (q *queryable) InnerInclude(include ...interface{}) Scope {
-	return q.Identity().InnerInclude(include...)
-}
-func (q *queryable) FullInclude(include interface{}, nullRecords interface{}) Scope {
-	return q.Identity().FullInclude(include, nullRecords)
-}
-func (q *queryable) RightInclude(include interface{}, nullRecords interface{}) Scope {
-	return q.Identity().RightInclude(include, nullRecords)
-}
-func (q *queryable) IncludeSql(il IncludeList, query string, args ...interface{}) Scope {
-	return q.Identity().IncludeSql(il, query, args...)
-}
@@ -16,7 +16,8 @@ appended.
 
 Ids are identified with the field name "Id" and the database
 column "id", while the timestamp fields are "CreatedAt" and
-"UpdatedAt".
+"UpdatedAt". Join tables are the two table names in alphabetical
+order separated by an underscore.
 */
 func NewRailsConfig() *Config {
 	c := new(Config)
@@ -30,6 +31,7 @@ func NewRailsConfig() *Config {
 	c.IdName = func(s string) (string, string) {
 		return "Id", "id"
 	}
+	c.JoinTableName = sortedJoinTable
 	c.CreatedColumn = "CreatedAt"
 	c.UpdatedColumn = "UpdatedAt"
 
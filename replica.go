@@ -0,0 +1,191 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/dchest/cache"
+)
+
+// A replica is a read-only copy of the primary database, it keeps its
+// own prepared statement cache as statements are prepared per *sql.DB
+type replica struct {
+	DB         *sql.DB
+	QueryCache *cache.Cache
+	healthy    bool
+}
+
+type replicaSet struct {
+	sync.Mutex
+	members []*replica
+	next    int
+}
+
+/*
+AddReplica opens a connection to a read replica of the database using the
+same driver as the Connection. Once replicas are added, the read terminals
+(Find, Retrieve, RetrieveAll, Count and Pluck) will be sent to the replicas
+in turn, skipping any replica that is failing. Writes, and any queries run
+inside of a transaction, will always be sent to the primary database. You
+can use the OnPrimary Scope to read from the primary when you need to read
+your own writes.
+
+  conn.AddReplica("user=app host=replica1 dbname=app")
+  conn.AddReplica("user=app host=replica2 dbname=app")
+*/
+func (c *Connection) AddReplica(connector string) error {
+	db, err := sql.Open(c.driverName, connector)
+	if err != nil {
+		return err
+	}
+	db.SetMaxIdleConns(100)
+
+	c.replicas.Lock()
+	defer c.replicas.Unlock()
+	c.replicas.members = append(c.replicas.members, &replica{
+		DB:         db,
+		QueryCache: newQueryCache(4096),
+		healthy:    true,
+	})
+
+	return nil
+}
+
+/*
+CheckReplicas pings each replica, replicas that respond will be used for
+reads, while replicas that don't will be skipped until a later check finds
+them responding. Replicas are also skipped when a query to them fails from
+a connection error, so you should call this periodically to bring them back.
+
+  go func() {
+    for _ = range time.Tick(30 * time.Second) {
+      conn.CheckReplicas()
+    }
+  }()
+*/
+func (c *Connection) CheckReplicas() {
+	if c.replicas == nil {
+		return
+	}
+	c.replicas.Lock()
+	members := c.replicas.members
+	c.replicas.Unlock()
+
+	for _, r := range members {
+		err := r.DB.Ping()
+		c.replicas.Lock()
+		r.healthy = err == nil
+		c.replicas.Unlock()
+	}
+}
+
+// replica picks the next healthy replica that a read should be sent to,
+// it returns nil when reads should go to the primary
+func (c *Connection) replica(primary bool) *replica {
+	if primary || c.currentTx() != nil {
+		return nil
+	}
+
+	rs := c.replicas
+	if rs == nil {
+		return nil
+	}
+	rs.Lock()
+	defer rs.Unlock()
+	for i := 0; i < len(rs.members); i++ {
+		r := rs.members[rs.next%len(rs.members)]
+		rs.next++
+		if r.healthy {
+			return r
+		}
+	}
+	return nil
+}
+
+// failed checks whether an error was caused by not being able to
+// talk to the replica, if it was, the replica is marked unhealthy
+func (rs *replicaSet) failed(r *replica, err error) bool {
+	var ne net.Error
+	if err == driver.ErrBadConn || errors.As(err, &ne) {
+		rs.Lock()
+		r.healthy = false
+		rs.Unlock()
+		return true
+	}
+	return false
+}
+
+func (rs *replicaSet) close() error {
+	rs.Lock()
+	defer rs.Unlock()
+
+	var err error
+	for _, r := range rs.members {
+		if e := r.DB.Close(); e != nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (rs *replicaSet) resize(n int) {
+	rs.Lock()
+	defer rs.Unlock()
+
+	for _, r := range rs.members {
+		r.QueryCache.Reconfigure(queryCacheConfig(n))
+	}
+}
+
+// readQueryContext is QueryContext for reads, which are sent to a replica
+// when there are replicas, falling back to the primary if the replica fails
+func (c *Connection) readQueryContext(ctx context.Context, primary bool, query string, args ...interface{}) (*sql.Rows, error) {
+	r := c.replica(primary)
+	if r == nil {
+		return c.QueryContext(ctx, query, args...)
+	}
+
+	start := time.Now()
+	var rows *sql.Rows
+	stmt, cached, err := getQuery(ctx, r.DB, r.QueryCache, query)
+	if err == nil {
+		rows, err = stmt.QueryContext(ctx, args...)
+	}
+	c.finishStatement(ctx, query, args, start, cached, nil, err)
+	if err != nil && c.replicas.failed(r, err) {
+		return c.QueryContext(ctx, query, args...)
+	}
+	return rows, err
+}
+
+// readQueryRowContext is QueryRowContext for reads, see readQueryContext
+func (c *Connection) readQueryRowContext(ctx context.Context, primary bool, query string, args ...interface{}) *sql.Row {
+	r := c.replica(primary)
+	if r == nil {
+		return c.QueryRowContext(ctx, query, args...)
+	}
+
+	start := time.Now()
+	stmt, cached, err := getQuery(ctx, r.DB, r.QueryCache, query)
+	if err != nil && c.replicas.failed(r, err) {
+		c.finishStatement(ctx, query, args, start, cached, nil, err)
+		return c.QueryRowContext(ctx, query, args...)
+	}
+
+	var row *sql.Row
+	if err == nil {
+		row = stmt.QueryRowContext(ctx, args...)
+	} else {
+		row = r.DB.QueryRowContext(ctx, query, args...)
+	}
+	c.finishStatement(ctx, query, args, start, cached, nil, row.Err())
+	if c.replicas.failed(r, row.Err()) {
+		return c.QueryRowContext(ctx, query, args...)
+	}
+	return row
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"database/sql/driver"
+	"errors"
+	. "github.com/acsellers/assert"
+	"testing"
+)
+
+func TestReplicaSelection(t *testing.T) {
+	Within(t, func(test *Test) {
+		r1, r2 := &replica{healthy: true}, &replica{healthy: true}
+		c := &Connection{replicas: &replicaSet{members: []*replica{r1, r2}}}
+
+		test.Section("Round robin")
+		test.IsTrue(c.replica(false) == r1)
+		test.IsTrue(c.replica(false) == r2)
+		test.IsTrue(c.replica(false) == r1)
+
+		test.Section("Primary reads")
+		test.IsTrue(c.replica(true) == nil)
+
+		test.Section("Failing replicas are skipped")
+		test.IsFalse(c.replicas.failed(r1, errors.New("syntax error")))
+		test.IsTrue(c.replicas.failed(r1, driver.ErrBadConn))
+		test.IsTrue(c.replica(false) == r2)
+		test.IsTrue(c.replica(false) == r2)
+
+		r2.healthy = false
+		test.IsTrue(c.replica(false) == nil)
+
+		test.Section("Connections without replicas")
+		c = &Connection{QueryCache: newQueryCache(16)}
+		test.IsTrue(c.replica(false) == nil)
+		c.CacheSize(32)
+		c.CheckReplicas()
+	})
+}
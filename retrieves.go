@@ -1,6 +1,7 @@
 package db
 
 import (
+	"database/sql"
 	"errors"
 	"reflect"
 	"strings"
@@ -8,31 +9,45 @@ import (
 
 // Find looks for the record with primary key equal to val
 func (q *queryable) Find(id interface{}, val interface{}) error {
-	return q.EqualTo(q.source.ID.Column(), id).Retrieve(val)
+	qq := q.EqualTo(q.source.ID.Column(), id).(*queryable)
+	qq.ctx = qq.runContext("Find")
+	return qq.Retrieve(val)
 }
 
 func (q *queryable) Retrieve(val interface{}) error {
-	query, values := q.source.conn.Dialect.Query(q)
-	row := q.source.runQueryRow(query, values)
-
+	if err := q.readErr(); err != nil {
+		return err
+	}
 	if reflect.TypeOf(val).Kind() != reflect.Ptr {
 		return errors.New("Must Supply Ptr to Destination")
 	}
+	if q.including() {
+		return q.retrieveOne(val)
+	}
+	query, values := q.source.conn.Dialect.Query(q)
+	row := q.source.runReadRow(q.runContext("Retrieve"), q.primary, query, values)
+
 	value := reflect.ValueOf(val)
 	rfltr := reflector{value}
-	plan := q.source.mapPlan(rfltr)
+	plan := q.plan(rfltr)
 
 	e := row.Scan(plan.Items()...)
 	if e == nil {
 		e = q.Initialize(val, plan)
 		plan.Finalize(val)
 	}
+	if e == nil {
+		e = q.loadPreloads([]reflect.Value{value.Elem()})
+	}
 	return e
 }
 
 func (q *queryable) RetrieveAll(dest interface{}) error {
+	if err := q.readErr(); err != nil {
+		return err
+	}
 	query, values := q.source.conn.Dialect.Query(q)
-	rows, err := q.source.runQuery(query, values)
+	rows, err := q.source.runRead(q.runContext("RetrieveAll"), q.primary, query, values)
 	if err != nil {
 		return err
 	}
@@ -42,10 +57,23 @@ func (q *queryable) RetrieveAll(dest interface{}) error {
 	destSliceVal := destVal.Elem()
 	tempSliceVal := reflect.Zero(destSliceVal.Type())
 	element := destSliceVal.Type().Elem()
+	if q.including() {
+		included, err := q.retrieveIncluded(rows, element)
+		if err != nil {
+			return err
+		}
+		for _, record := range included {
+			tempSliceVal = reflect.Append(tempSliceVal, record.Elem())
+		}
+		destSliceVal.Set(tempSliceVal)
+		return q.loadPreloads(recordsOf(destSliceVal))
+	}
 	vn := reflect.New(element)
 	rfltr := reflector{vn}
-	plan := q.source.mapPlan(rfltr)
+	plan := q.plan(rfltr)
 	for rows.Next() {
+		// NULL columns leave their field alone, so clear the last record
+		vn.Elem().Set(reflect.Zero(element))
 		err = rows.Scan(plan.Items()...)
 		if err != nil {
 			return err
@@ -56,23 +84,26 @@ func (q *queryable) RetrieveAll(dest interface{}) error {
 		rfltr.item = reflect.New(element)
 	}
 	destSliceVal.Set(tempSliceVal)
-	return nil
+	return q.loadPreloads(recordsOf(destSliceVal))
 }
 
 func (q *queryable) Pluck(selection interface{}, val interface{}) error {
+	if err := q.readErr(); err != nil {
+		return err
+	}
 	qq := q.Identity().(*queryable)
 	switch sv := selection.(type) {
 	case string:
 		if strings.Index(sv, ".") == -1 {
-			sv = q.source.SqlName + "." + sv
+			sv = q.tableAlias() + "." + sv
 		}
 		qq.selection = []selector{selector{Formula: sv}}
 	case SqlFunc:
-		qq.selection = []selector{selector{Formula: sv.String()}}
+		qq.selection = []selector{selector{Formula: sv.Fragment(), Args: sv.Values()}}
 	}
 
 	query, values := qq.source.conn.Dialect.Query(qq)
-	rows, err := qq.source.runQuery(query, values)
+	rows, err := qq.source.runRead(qq.runContext("Pluck"), qq.primary, query, values)
 	if err != nil {
 		return err
 	}
@@ -94,3 +125,113 @@ func (q *queryable) Pluck(selection interface{}, val interface{}) error {
 
 	return err
 }
+
+func (q *queryable) PluckSeveral(columns []string, vals ...interface{}) error {
+	if err := q.readErr(); err != nil {
+		return err
+	}
+	if len(columns) != len(vals) {
+		return errors.New("PluckSeveral needs a destination for each column")
+	}
+	qq := q.Identity().(*queryable)
+	qq.selection = make([]selector, len(columns))
+	for i, column := range columns {
+		if strings.Index(column, ".") == -1 {
+			column = q.tableAlias() + "." + column
+		}
+		qq.selection[i] = selector{Formula: column}
+	}
+
+	query, values := qq.source.conn.Dialect.Query(qq)
+	rows, err := qq.source.runRead(qq.runContext("PluckSeveral"), qq.primary, query, values)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	destSliceVals := make([]reflect.Value, len(vals))
+	tempSliceVals := make([]reflect.Value, len(vals))
+	items := make([]interface{}, len(vals))
+	elements := make([]reflect.Value, len(vals))
+	for i, val := range vals {
+		destSliceVals[i] = reflect.ValueOf(val).Elem()
+		tempSliceVals[i] = reflect.Zero(destSliceVals[i].Type())
+		elements[i] = reflect.New(destSliceVals[i].Type().Elem())
+		items[i] = elements[i].Interface()
+	}
+	for rows.Next() {
+		err = rows.Scan(items...)
+		if err != nil {
+			return err
+		}
+		for i, vn := range elements {
+			tempSliceVals[i] = reflect.Append(tempSliceVals[i], vn.Elem())
+		}
+	}
+	for i, destSliceVal := range destSliceVals {
+		destSliceVal.Set(tempSliceVals[i])
+	}
+
+	return rows.Err()
+}
+
+func (q *queryable) Select(columns string, dest interface{}) error {
+	if err := q.readErr(); err != nil {
+		return err
+	}
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Ptr {
+		return errors.New("Must Supply Ptr to Destination")
+	}
+	qq := q.Identity().(*queryable)
+	qq.selection = []selector{selector{Formula: columns}}
+
+	query, values := qq.source.conn.Dialect.Query(qq)
+	rows, err := qq.source.runRead(qq.runContext("Select"), qq.primary, query, values)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	names, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	destElemVal := destVal.Elem()
+	if destElemVal.Kind() == reflect.Struct {
+		if !rows.Next() {
+			if err = rows.Err(); err != nil {
+				return err
+			}
+			return sql.ErrNoRows
+		}
+		plan := q.selectPlan(destVal, names)
+		if err = rows.Scan(plan.Items()...); err != nil {
+			return err
+		}
+		plan.Finalize(dest)
+		return nil
+	}
+
+	tempSliceVal := reflect.Zero(destElemVal.Type())
+	element := destElemVal.Type().Elem()
+	isPtr := element.Kind() == reflect.Ptr
+	if isPtr {
+		element = element.Elem()
+	}
+	for rows.Next() {
+		vn := reflect.New(element)
+		plan := q.selectPlan(vn, names)
+		if err = rows.Scan(plan.Items()...); err != nil {
+			return err
+		}
+		plan.Finalize(vn.Interface())
+		if isPtr {
+			tempSliceVal = reflect.Append(tempSliceVal, vn)
+		} else {
+			tempSliceVal = reflect.Append(tempSliceVal, vn.Elem())
+		}
+	}
+	destElemVal.Set(tempSliceVal)
+	return rows.Err()
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+/*
+A RetryPolicy tells a Connection to re-run operations that failed from a
+deadlock, lock wait timeout or serialization failure, which are classified
+by the Dialect's RetryableError function. Reads (Find, Retrieve, RetrieveAll,
+Count, Pluck) and the Delete and Update* terminals are re-run on their own,
+while queries inside of a transaction are not, because the database will have
+rolled back the whole transaction. Instead, the function passed to Transaction
+is re-run in a new transaction.
+
+  conn.RetryPolicy = &db.RetryPolicy{
+    Attempts: 4,
+    Backoff: 20 * time.Millisecond,
+    MaxBackoff: time.Second,
+  }
+*/
+type RetryPolicy struct {
+	// The number of times an operation will be attempted, including the
+	// first attempt
+	Attempts int
+	// The time to wait before the first retry, each following retry will
+	// wait twice as long as the previous retry
+	Backoff time.Duration
+	// The longest time to wait between retries, zero means there is no limit
+	MaxBackoff time.Duration
+}
+
+func (rp *RetryPolicy) wait(attempt int) time.Duration {
+	d := rp.Backoff
+	for i := 1; i < attempt && (rp.MaxBackoff == 0 || d < rp.MaxBackoff); i++ {
+		d *= 2
+	}
+	if rp.MaxBackoff != 0 && d > rp.MaxBackoff {
+		return rp.MaxBackoff
+	}
+	return d
+}
+
+// IsRetryable returns whether the Dialect considers the error to be a
+// transient failure, like a deadlock, that will succeed if tried again.
+func (c *Connection) IsRetryable(err error) bool {
+	return err != nil && c.Dialect.RetryableError(err)
+}
+
+// retry runs f until it succeeds, returns an error that isn't retryable or
+// the RetryPolicy runs out of attempts
+func (c *Connection) retry(ctx context.Context, f func() error) error {
+	err := f()
+	if c.RetryPolicy == nil {
+		return err
+	}
+
+	for attempt := 1; attempt < c.RetryPolicy.Attempts && c.IsRetryable(err); attempt++ {
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(c.RetryPolicy.wait(attempt)):
+		}
+		err = f()
+	}
+	return err
+}
+
+// retryStatement is retry for single statements, which are not retried
+// inside of a transaction
+func (c *Connection) retryStatement(ctx context.Context, f func() error) error {
+	if c.currentTx() != nil {
+		return f()
+	}
+	return c.retry(ctx, f)
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/acsellers/assert"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+func TestRetryableError(t *testing.T) {
+	Within(t, func(test *Test) {
+		test.Section("Mysql")
+		d := registeredDialects["mysql"]
+		test.IsTrue(d.RetryableError(&mysql.MySQLError{Number: 1213}))
+		test.IsTrue(d.RetryableError(&mysql.MySQLError{Number: 1205}))
+		test.IsFalse(d.RetryableError(&mysql.MySQLError{Number: 1062}))
+		test.IsFalse(d.RetryableError(errors.New("deadlock")))
+
+		test.Section("Postgres")
+		d = registeredDialects["postgres"]
+		test.IsTrue(d.RetryableError(&pq.Error{Code: "40001"}))
+		test.IsTrue(d.RetryableError(&pq.Error{Code: "40P01"}))
+		test.IsFalse(d.RetryableError(&pq.Error{Code: "23505"}))
+
+		test.Section("Sqlite")
+		d = registeredDialects["sqlite3"]
+		test.IsTrue(d.RetryableError(errors.New("database is locked")))
+		test.IsFalse(d.RetryableError(errors.New("no such table: posts")))
+	})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	Within(t, func(test *Test) {
+		rp := &RetryPolicy{Attempts: 5, Backoff: time.Millisecond, MaxBackoff: 3 * time.Millisecond}
+		test.AreEqual(time.Millisecond, rp.wait(1))
+		test.AreEqual(2*time.Millisecond, rp.wait(2))
+		test.AreEqual(3*time.Millisecond, rp.wait(3))
+		test.AreEqual(3*time.Millisecond, rp.wait(10))
+
+		test.Section("Retrying")
+		c := &Connection{Dialect: registeredDialects["mysql"], RetryPolicy: rp}
+		attempts := 0
+		e := c.retry(context.Background(), func() error {
+			attempts++
+			if attempts < 3 {
+				return &mysql.MySQLError{Number: 1213}
+			}
+			return nil
+		})
+		test.NoError(e)
+		test.AreEqual(3, attempts)
+
+		test.Section("Running out of attempts")
+		attempts = 0
+		e = c.retry(context.Background(), func() error {
+			attempts++
+			return &mysql.MySQLError{Number: 1205}
+		})
+		test.IsNotNil(e)
+		test.AreEqual(5, attempts)
+
+		test.Section("Errors that aren't retryable")
+		attempts = 0
+		e = c.retry(context.Background(), func() error {
+			attempts++
+			return errors.New("syntax error")
+		})
+		test.IsNotNil(e)
+		test.AreEqual(1, attempts)
+	})
+}
@@ -15,6 +15,8 @@ type selector struct {
 	Formula string
 	Alias   string
 	Value   interface{}
+	// Args are the values bound to the ?'s of the Formula
+	Args []interface{}
 }
 
 func (s *selector) String() string {
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A compoundTable is the result of a set operation between two Scopes,
+// it is selected from as a derived table
+type compoundTable struct {
+	op          string
+	left, right *queryable
+}
+
+func (ct *compoundTable) Fragment() string {
+	left, _ := compoundMember(ct.left)
+	right, _ := compoundMember(ct.right)
+	return "(" + left + " " + ct.op + " " + right + ")"
+}
+func (ct *compoundTable) Values() []interface{} {
+	_, vals := compoundMember(ct.left)
+	_, right := compoundMember(ct.right)
+	return append(vals, right...)
+}
+func (ct *compoundTable) String() string {
+	return withVars(ct.Fragment(), ct.Values())
+}
+
+// compoundMember is the SQL for one side of a set operation, Scopes with
+// an ordering, limit or offset are wrapped since not every database allows
+// them within a set operation
+func compoundMember(q *queryable) (string, []interface{}) {
+	query, vals := selectSql(q)
+	if len(q.order) > 0 || q.limit != 0 || q.offset != 0 {
+		query = "SELECT * FROM (" + query + ") AS " + q.source.SqlName
+	}
+	return query, vals
+}
+
+/*
+Union returns a Scope for the records in either the Scope or the scope passed,
+without duplicates. The Scopes must select the same columns. The conditions,
+orderings and limits of the returned Scope apply to the combined records.
+
+  // the 5 most viewed posts and the 5 newest posts, newest first
+  Posts.Order("views DESC").Limit(5).
+    Union(Posts.Order("created_at DESC").Limit(5)).
+    Order("created_at DESC")
+*/
+func (q *queryable) Union(scope Scope) Scope {
+	return q.setOperation("UNION", scope)
+}
+
+// UnionAll is a Union that keeps the duplicate records
+func (q *queryable) UnionAll(scope Scope) Scope {
+	return q.setOperation("UNION ALL", scope)
+}
+
+// Intersect returns a Scope for the records that are in both Scopes
+func (q *queryable) Intersect(scope Scope) Scope {
+	return q.setOperation("INTERSECT", scope)
+}
+
+// Except returns a Scope for the records of the Scope that aren't in the
+// scope passed
+func (q *queryable) Except(scope Scope) Scope {
+	return q.setOperation("EXCEPT", scope)
+}
+
+func (q *queryable) setOperation(op string, scope Scope) Scope {
+	nq := &queryable{source: q.source, ctx: q.ctx, primary: q.primary, err: q.err}
+	if nq.err != nil {
+		return nq
+	}
+	if err := q.source.conn.Dialect.SetOperation(op); err != nil {
+		nq.err = err
+		return nq
+	}
+	other := scopeOf(scope)
+	if other == nil {
+		nq.err = fmt.Errorf("Can't %s a %T with a Scope", op, scope)
+		return nq
+	}
+	if other.err != nil {
+		nq.err = other.err
+		return nq
+	}
+
+	left, right := q.columnNames(), other.columnNames()
+	if len(left) != len(right) {
+		nq.err = fmt.Errorf(
+			"Can't %s a Scope selecting %d columns with a Scope selecting %d columns",
+			op, len(left), len(right),
+		)
+		return nq
+	}
+	for i := range left {
+		if left[i] != "" && right[i] != "" && left[i] != right[i] {
+			nq.err = fmt.Errorf("Can't %s column %s with column %s", op, left[i], right[i])
+			return nq
+		}
+	}
+
+	nq.from = &fromTable{&compoundTable{op, q, other}, q.source.SqlName}
+	return nq
+}
+
+// columnNames are the names of the columns selected by the Scope
+func (q *queryable) columnNames() []string {
+	if len(q.selection) > 0 {
+		return q.selectedColumns()
+	}
+
+	columns := q.source.selectColumns()
+	for i, column := range columns {
+		columns[i] = column[strings.LastIndex(column, ".")+1:]
+	}
+	return columns
+}
@@ -10,7 +10,8 @@ I could think of. Table and Column names are lowercase
 versions of struct and field names, foreign keys are
 lowercased field names with 'id' appended. Primary key
 names are Id and id, with timestamps of Creation and
-Modified.
+Modified. Join tables are the two table names in alphabetical
+order.
 */
 func NewSimpleConfig() *Config {
 	c := new(Config)
@@ -25,6 +26,12 @@ func NewSimpleConfig() *Config {
 	c.IdName = func(s string) (string, string) {
 		return "Id", "id"
 	}
+	c.JoinTableName = func(t, ot string) string {
+		if ot < t {
+			t, ot = ot, t
+		}
+		return t + ot
+	}
 	c.CreatedColumn = "Creation"
 	c.UpdatedColumn = "Modified"
 
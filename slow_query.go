@@ -0,0 +1,231 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// the number of durations kept for each fingerprint to calculate
+// the percentiles from, the count and max are always exact
+const slowQuerySamples = 1024
+
+/*
+A SlowQuery is the aggregate of every recorded run of a statement that was
+slower than the Connection's SlowQueryThreshold. Statements are grouped by
+their fingerprint, the Mapper and the terminal method that ran them.
+*/
+type SlowQuery struct {
+	// The SQL with literals replaced by ? and IN lists collapsed to IN (?)
+	Fingerprint string `json:"fingerprint"`
+	// The name of the Mapper that ran the statement, empty when the
+	// statement was run directly on the Connection
+	Mapper string `json:"mapper,omitempty"`
+	// The table of the Mapper that ran the statement
+	Table string `json:"table,omitempty"`
+	// The terminal method that ran the statement (RetrieveAll, Count, ...)
+	Method string `json:"method,omitempty"`
+	// The number of times the statement was slow
+	Count int `json:"count"`
+	// The total time spent in the slow runs of the statement
+	Total time.Duration `json:"total_ns"`
+	P50   time.Duration `json:"p50_ns"`
+	P95   time.Duration `json:"p95_ns"`
+	Max   time.Duration `json:"max_ns"`
+	// The values bound to the slowest run of the statement
+	ExampleArgs []interface{} `json:"example_args"`
+}
+
+// A SlowQueryReport is the list of SlowQueries for a Connection, with the
+// statements that took the most total time first.
+type SlowQueryReport []SlowQuery
+
+type slowQueryKey struct {
+	fingerprint, mapper, method string
+}
+
+type slowQueryStats struct {
+	SlowQuery
+	samples []time.Duration
+	next    int
+}
+
+type slowQueryLog struct {
+	sync.Mutex
+	stats map[slowQueryKey]*slowQueryStats
+}
+
+func newSlowQueryLog() *slowQueryLog {
+	return &slowQueryLog{stats: make(map[slowQueryKey]*slowQueryStats)}
+}
+
+func (sl *slowQueryLog) record(o *origin, query string, args []interface{}, d time.Duration) {
+	key := slowQueryKey{fingerprint: Fingerprint(query)}
+	if o != nil {
+		key.mapper, key.method = o.source.Name, o.method
+	}
+
+	sl.Lock()
+	defer sl.Unlock()
+	st, ok := sl.stats[key]
+	if !ok {
+		st = &slowQueryStats{SlowQuery: SlowQuery{
+			Fingerprint: key.fingerprint,
+			Mapper:      key.mapper,
+			Method:      key.method,
+		}}
+		if o != nil {
+			st.Table = o.source.SqlName
+		}
+		sl.stats[key] = st
+	}
+
+	st.Count++
+	st.Total += d
+	if d >= st.Max {
+		st.Max = d
+		st.ExampleArgs = args
+	}
+	if len(st.samples) < slowQuerySamples {
+		st.samples = append(st.samples, d)
+	} else {
+		st.samples[st.next] = d
+		st.next = (st.next + 1) % slowQuerySamples
+	}
+}
+
+func (sl *slowQueryLog) report() SlowQueryReport {
+	sl.Lock()
+	defer sl.Unlock()
+
+	report := make(SlowQueryReport, 0, len(sl.stats))
+	for _, st := range sl.stats {
+		samples := append([]time.Duration{}, st.samples...)
+		sort.Sort(durations(samples))
+		sq := st.SlowQuery
+		sq.P50 = percentile(samples, 50)
+		sq.P95 = percentile(samples, 95)
+		report = append(report, sq)
+	}
+	sort.Sort(report)
+
+	return report
+}
+
+func (sl *slowQueryLog) reset() {
+	sl.Lock()
+	sl.stats = make(map[slowQueryKey]*slowQueryStats)
+	sl.Unlock()
+}
+
+/*
+SlowQueries returns the report of the statements that took longer than the
+SlowQueryThreshold of the Connection. Transactions started from the Connection
+share its report.
+
+  conn.SlowQueryThreshold = 200 * time.Millisecond
+  ...
+  conn.SlowQueries().WriteText(os.Stderr)
+*/
+func (c *Connection) SlowQueries() SlowQueryReport {
+	return c.slowQueries.report()
+}
+
+// ResetSlowQueries clears the recorded slow queries
+func (c *Connection) ResetSlowQueries() {
+	c.slowQueries.reset()
+}
+
+// WriteText writes the report in a readable format, one block per fingerprint
+func (r SlowQueryReport) WriteText(w io.Writer) error {
+	for _, sq := range r {
+		_, err := fmt.Fprintf(w, "%s\n", sq.Fingerprint)
+		if err != nil {
+			return err
+		}
+		if sq.Mapper != "" {
+			_, err = fmt.Fprintf(w, "  %s (%s) %s\n", sq.Mapper, sq.Table, sq.Method)
+			if err != nil {
+				return err
+			}
+		}
+		_, err = fmt.Fprintf(
+			w,
+			"  count: %d total: %v p50: %v p95: %v max: %v\n  args: %v\n\n",
+			sq.Count, sq.Total, sq.P50, sq.P95, sq.Max, sq.ExampleArgs,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the report as a JSON array of SlowQueries, durations
+// are written in nanoseconds
+func (r SlowQueryReport) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+func (r SlowQueryReport) Len() int {
+	return len(r)
+}
+func (r SlowQueryReport) Less(i, j int) bool {
+	if r[i].Total == r[j].Total {
+		return r[i].Fingerprint < r[j].Fingerprint
+	}
+	return r[i].Total > r[j].Total
+}
+func (r SlowQueryReport) Swap(i, j int) {
+	r[i], r[j] = r[j], r[i]
+}
+
+type durations []time.Duration
+
+func (d durations) Len() int           { return len(d) }
+func (d durations) Less(i, j int) bool { return d[i] < d[j] }
+func (d durations) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+
+// percentile uses the nearest rank of the sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+var (
+	fingerprintString = regexp.MustCompile(`'(?:[^']|'')*'`)
+	fingerprintNumber = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	fingerprintBind   = regexp.MustCompile(`\$\d+`)
+	fingerprintSpace  = regexp.MustCompile(`\s+`)
+	fingerprintInList = regexp.MustCompile(`(?i)\bIN \(\?(?:, ?\?)*\)`)
+	fingerprintPunct  = strings.NewReplacer(" ,", ",", "( ", "(", " )", ")")
+)
+
+/*
+Fingerprint normalizes a query so that runs of it with different values can
+be grouped together. String and number literals and the numbered bind
+variables of postgres are replaced with ?, and lists of values for IN
+are collapsed.
+
+  db.Fingerprint("SELECT * FROM posts WHERE id IN ($1, $2, $3) AND title = 'hi'")
+  // SELECT * FROM posts WHERE id IN (?) AND title = ?
+*/
+func Fingerprint(query string) string {
+	fp := fingerprintString.ReplaceAllString(query, "?")
+	fp = fingerprintBind.ReplaceAllString(fp, "?")
+	fp = fingerprintNumber.ReplaceAllString(fp, "?")
+	fp = fingerprintSpace.ReplaceAllString(strings.TrimSpace(fp), " ")
+	fp = fingerprintPunct.Replace(fp)
+	return fingerprintInList.ReplaceAllString(fp, "IN (?)")
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/acsellers/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	Within(t, func(test *Test) {
+		test.AreEqual(
+			"SELECT * FROM posts WHERE id IN (?) AND title = ?",
+			Fingerprint("SELECT * FROM posts WHERE id IN ($1, $2, $3) AND title = 'it''s'"),
+		)
+		test.AreEqual(
+			"SELECT * FROM posts WHERE posts.id IN (?) LIMIT ?",
+			Fingerprint("SELECT *\n  FROM posts\n  WHERE posts.id IN (?,?, ?)\n  LIMIT 10"),
+		)
+		test.AreEqual(
+			"SELECT t1.views FROM posts t1 WHERE t1.views > ?",
+			Fingerprint("SELECT t1.views FROM posts t1 WHERE t1.views > 2.5"),
+		)
+	})
+}
+
+func TestSlowQueries(t *testing.T) {
+	Within(t, func(test *Test) {
+		c := &Connection{SlowQueryThreshold: time.Millisecond, slowQueries: newSlowQueryLog()}
+		posts := &source{Name: "Post", SqlName: "posts"}
+		ctx := withOrigin(context.Background(), &origin{source: posts, method: "RetrieveAll"})
+
+		for i := 1; i <= 20; i++ {
+			start := time.Now().Add(-time.Duration(i) * time.Millisecond)
+			c.finishStatement(ctx, "SELECT * FROM posts WHERE id IN (?, ?)", []interface{}{i, i + 1}, start, true, nil, nil)
+		}
+		c.finishStatement(ctx, "SELECT * FROM posts", nil, time.Now(), true, nil, nil)
+
+		report := c.SlowQueries()
+		test.AreEqual(1, len(report))
+		if len(report) == 1 {
+			sq := report[0]
+			test.AreEqual("SELECT * FROM posts WHERE id IN (?)", sq.Fingerprint)
+			test.AreEqual("Post", sq.Mapper)
+			test.AreEqual("posts", sq.Table)
+			test.AreEqual("RetrieveAll", sq.Method)
+			test.AreEqual(20, sq.Count)
+			test.IsTrue(sq.P50 >= 10*time.Millisecond && sq.P50 < 11*time.Millisecond)
+			test.IsTrue(sq.P95 >= 19*time.Millisecond && sq.P95 < 20*time.Millisecond)
+			test.IsTrue(sq.Max >= 20*time.Millisecond)
+			test.AreEqual(20, sq.ExampleArgs[0])
+		}
+
+		test.Section("Dumping")
+		var buf bytes.Buffer
+		test.NoError(report.WriteText(&buf))
+		test.IsTrue(strings.Contains(buf.String(), "Post (posts) RetrieveAll"))
+		buf.Reset()
+		test.NoError(report.WriteJSON(&buf))
+		var decoded []map[string]interface{}
+		test.NoError(json.Unmarshal(buf.Bytes(), &decoded))
+		test.AreEqual(1, len(decoded))
+
+		c.ResetSlowQueries()
+		test.AreEqual(0, len(c.SlowQueries()))
+	})
+}
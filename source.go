@@ -1,9 +1,12 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"github.com/acsellers/inflections"
 	"reflect"
 	"strings"
+	"time"
 )
 
 type source struct {
@@ -32,8 +35,15 @@ func (sm *sourceMapping) Column() string {
 	return sm.SqlTable + "." + sm.SqlColumn
 }
 
+// Aliased is true when the field isn't named for the struct it holds, the
+// names of has many fields may be plural and unexported structs lowercase
 func (sm *sourceMapping) Aliased() bool {
-	return !strings.HasSuffix(sm.FullName, ":"+sm.structOptions.Name)
+	name := sm.structOptions.Name
+	if sm.Kind == reflect.Slice {
+		name = inflections.Singularize(name)
+	}
+	typeName := sm.FullName[strings.LastIndex(sm.FullName, ":")+1:]
+	return !strings.EqualFold(typeName, name) && !strings.EqualFold(typeName, sm.structOptions.Name)
 }
 func (sm *sourceMapping) MappedColumn() bool {
 	return sm.structOptions != nil && sm.ColumnInfo != nil
@@ -62,6 +72,17 @@ type structOptions struct {
 	Relation     *source
 	ForeignKey   *ColumnInfo
 	IsForeignKey bool
+	// Through is the join table of a has and belongs to many relation
+	Through *joinTable
+}
+
+// A joinTable holds the keys of both sides of a has and belongs to many
+// relation, Key is the column for the struct holding the relation field
+// and ForeignKey is the column for the related struct
+type joinTable struct {
+	Table      string
+	Key        string
+	ForeignKey string
 }
 
 // ColumnInfo is the data returned by a ColumnsInTable function which is
@@ -87,16 +108,108 @@ type ColumnInfo struct {
 	Number int
 }
 
-func (s *source) runQuery(query string, values []interface{}) (*sql.Rows, error) {
-	return s.conn.Query(query, values...)
+func (s *source) runQuery(ctx context.Context, query string, values []interface{}) (*sql.Rows, error) {
+	return s.query(ctx, true, query, values)
+}
+
+func (s *source) runQueryRow(ctx context.Context, query string, values []interface{}) *row {
+	return s.queryRow(ctx, true, query, values)
+}
+
+func (s *source) runExec(ctx context.Context, query string, values []interface{}) (sql.Result, error) {
+	return s.exec(ctx, query, values)
+}
+
+// runUpdate is runExec for UPDATE and DELETE statements, which are
+// retried if they fail from a deadlock and the Connection has a RetryPolicy
+func (s *source) runUpdate(ctx context.Context, query string, values []interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := s.conn.retryStatement(ctx, func() error {
+		var e error
+		result, e = s.exec(ctx, query, values)
+		return e
+	})
+	return result, err
+}
+
+// runRead is runQuery for read-only queries, which may be sent to a replica
+// unless primary is set, and are retried like runUpdate
+func (s *source) runRead(ctx context.Context, primary bool, query string, values []interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := s.conn.retryStatement(ctx, func() error {
+		var e error
+		rows, e = s.query(ctx, primary, query, values)
+		return e
+	})
+	return rows, err
 }
 
-func (s *source) runQueryRow(query string, values []interface{}) *sql.Row {
-	return s.conn.QueryRow(query, values...)
+// runReadRow is runQueryRow for read-only queries, see runRead
+func (s *source) runReadRow(ctx context.Context, primary bool, query string, values []interface{}) *row {
+	var r *row
+	s.conn.retryStatement(ctx, func() error {
+		r = s.queryRow(ctx, primary, query, values)
+		return r.Err()
+	})
+	return r
 }
 
-func (s *source) runExec(query string, values []interface{}) (sql.Result, error) {
-	return s.conn.Exec(query, values...)
+// query, queryRow and exec run a statement through the hooks of the Connection
+func (s *source) query(ctx context.Context, primary bool, query string, values []interface{}) (*sql.Rows, error) {
+	st, err := s.before(ctx, query, values)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	rows, err := s.conn.readQueryContext(ctx, primary, st.Query, st.Args...)
+	s.after(st, nil, err, start)
+	return rows, err
+}
+
+func (s *source) queryRow(ctx context.Context, primary bool, query string, values []interface{}) *row {
+	st, err := s.before(ctx, query, values)
+	if err != nil {
+		return &row{err: err}
+	}
+
+	start := time.Now()
+	r := s.conn.readQueryRowContext(ctx, primary, st.Query, st.Args...)
+	s.after(st, nil, r.Err(), start)
+	return &row{Row: r}
+}
+
+func (s *source) exec(ctx context.Context, query string, values []interface{}) (sql.Result, error) {
+	st, err := s.before(ctx, query, values)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	result, err := s.conn.ExecContext(ctx, st.Query, st.Args...)
+	s.after(st, result, err, start)
+	return result, err
+}
+
+// A row is a *sql.Row that can also be an error from a before hook
+// stopping the query
+type row struct {
+	*sql.Row
+	err error
+}
+
+func (r *row) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Row.Scan(dest...)
+}
+
+func (r *row) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Row.Err()
 }
 
 func (s *source) loadRelated() {
@@ -139,6 +252,9 @@ func (s *source) locateForeignKeys() {
 						}
 					}
 				}
+				if f.ForeignKey == nil {
+					s.locateJoinTable(f)
+				}
 			} else {
 				// find belongs to relation fields
 				kn := s.conn.Config.ForeignKeyName(f.structOptions.Name, f.FullName)
@@ -162,6 +278,39 @@ func (s *source) locateForeignKeys() {
 		}
 	}
 }
+
+// locateJoinTable finds the join table of a has and belongs to many
+// relation, which is named by a db_join_table tag on either side of the
+// relation, or by the Config when the related struct has a slice of this one.
+// The key columns can be named with db_join_key and db_join_foreign_key tags.
+func (s *source) locateJoinTable(f *sourceMapping) {
+	table, _ := f.Options["_join_table"].(string)
+	for _, rf := range f.Relation.Fields {
+		if table != "" {
+			break
+		}
+		if rf.ColumnInfo == nil && rf.Kind == reflect.Slice && rf.FullName == s.FullName {
+			if table, _ = rf.Options["_join_table"].(string); table == "" {
+				table = s.conn.Config.joinTableName(s.SqlName, f.Relation.SqlName)
+			}
+		}
+	}
+	if table == "" {
+		return
+	}
+	f.Through = &joinTable{
+		Table:      table,
+		Key:        s.conn.Config.ForeignKeyName(s.Name, s.FullName),
+		ForeignKey: s.conn.Config.ForeignKeyName(f.Relation.Name, f.Relation.FullName),
+	}
+	if key, _ := f.Options["_join_key"].(string); key != "" {
+		f.Through.Key = key
+	}
+	if fk, _ := f.Options["_join_foreign_key"].(string); fk != "" {
+		f.Through.ForeignKey = fk
+	}
+}
+
 func (s *source) refreshRelated(sn string) {
 	ns := s.conn.mappedStructs[sn]
 	for _, f := range s.Fields {
@@ -1,7 +1,11 @@
 package db
 
 import (
+	"database/sql"
+	"errors"
 	"reflect"
+	"regexp"
+	"strings"
 
 	_ "code.google.com/p/go-sqlite/go1/sqlite3"
 )
@@ -72,3 +76,67 @@ func (d sqliteDialect) Query(scope Scope) (string, []interface{}) {
 	out, args := d.Base.Query(scope)
 	return out, args
 }
+
+// sqliteBusy is the SQLITE_BUSY result code
+const sqliteBusy = 5
+
+// Sqlite returns SQLITE_BUSY when another connection is holding a lock on
+// the database, which can be retried
+func (d sqliteDialect) RetryableError(err error) bool {
+	var ce interface {
+		Code() int
+	}
+	if errors.As(err, &ce) {
+		return ce.Code() == sqliteBusy
+	}
+	return err != nil && strings.Contains(err.Error(), "database is locked")
+}
+
+// Sqlite locks the whole database for a transaction instead of rows, so
+// there is no locking clause to add
+func (d sqliteDialect) Lock(scope Scope) (string, error) {
+	return "", nil
+}
+
+// Sqlite has EXPLAIN QUERY PLAN, but no way to analyze a query
+func (d sqliteDialect) Explain(query string, analyze bool) (string, error) {
+	if analyze {
+		return "", errors.New("sqlite3 does not support EXPLAIN ANALYZE")
+	}
+	return "EXPLAIN QUERY PLAN " + query, nil
+}
+
+// Newer versions of sqlite return the id and parent of each step, which
+// are used to arrange the steps, older versions only return the steps
+func (d sqliteDialect) ParsePlan(rows *sql.Rows) (*QueryPlan, error) {
+	results, err := scanStrings(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &QueryPlan{}
+	nodes := make(map[string]*PlanNode)
+	for _, result := range results {
+		pn := sqlitePlanNode(result["detail"])
+		nodes[result["id"]] = pn
+		if parent, ok := nodes[result["parent"]]; ok && result["parent"] != "0" {
+			parent.Children = append(parent.Children, pn)
+		} else {
+			plan.Nodes = append(plan.Nodes, pn)
+		}
+	}
+	return plan, nil
+}
+
+var sqlitePlanDetail = regexp.MustCompile(`^(SCAN|SEARCH)(?: TABLE)? (\S+)(?: AS \S+)?(?: USING (?:COVERING )?(?:INDEX (\S+)|(INTEGER PRIMARY KEY)))?`)
+
+func sqlitePlanNode(detail string) *PlanNode {
+	pn := &PlanNode{Type: detail, Detail: detail}
+	if m := sqlitePlanDetail.FindStringSubmatch(detail); m != nil {
+		pn.Type, pn.Table, pn.Index = m[1], m[2], m[3]
+		if m[4] != "" {
+			pn.Index = m[4]
+		}
+	}
+	return pn
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// fromTable is a table or Scope that a Scope selects from in place of the
+// Mapper's table
+type fromTable struct {
+	table interface{}
+	alias string
+}
+
+func (ft *fromTable) Fragment() string {
+	switch table := ft.table.(type) {
+	case Scope:
+		return holderFor(table) + " AS " + ft.alias
+	case SqlBit:
+		return table.Fragment() + " AS " + ft.alias
+	default:
+		if fmt.Sprint(table) == ft.alias {
+			return ft.alias
+		}
+		return fmt.Sprint(table) + " AS " + ft.alias
+	}
+}
+func (ft *fromTable) Values() []interface{} {
+	switch table := ft.table.(type) {
+	case Scope:
+		return valuesFor(table)
+	case SqlBit:
+		return table.Values()
+	default:
+		return nil
+	}
+}
+func (ft *fromTable) String() string {
+	return withVars(ft.Fragment(), ft.Values())
+}
+
+/*
+From selects the records from a Scope or the SQL for a table instead of the
+Mapper's table. The results are still mapped into the Mapper's struct, so the
+table needs to have the columns of the Mapper's table. If the alias is blank,
+the Mapper's table name is used.
+
+  // find the posts in the 10 most viewed that are by the user
+  popular := Posts.Order("views DESC").Limit(10)
+  Posts.From(popular, "popular").EqualTo("user_id", userId)
+  // SELECT popular.id, ... FROM (SELECT posts.id, ... FROM posts ORDER BY views DESC LIMIT 10) AS popular
+  //   WHERE user_id = ?
+*/
+func (q *queryable) From(table interface{}, alias string) Scope {
+	nq := q.Identity().(*queryable)
+	if alias == "" {
+		alias = q.source.SqlName
+	}
+	nq.from = &fromTable{table, alias}
+	nq.useSubqueries(table)
+	return nq
+}
+
+/*
+Columns sets the columns or formulas that the Scope selects, columns that
+aren't qualified by a table are qualified with the Mapper's table, and * is
+every mapped column of the Mapper's table. Columns is mostly useful for Scopes
+that are used as subqueries, or to select Windows. Selected columns are put
+into the struct fields for the columns with the same name.
+
+  // find users that have a post with more than 100 views
+  Users.In("id", Posts.Cond("views", db.GT, 100).Columns("user_id"))
+
+  // the latest post of each user
+  latest := db.Func("ROW_NUMBER()").Over("user_id", "id DESC").As("rn")
+  Posts.From(Posts.Columns("*", latest), "ranked").EqualTo("rn", 1)
+*/
+func (q *queryable) Columns(columns ...interface{}) Scope {
+	nq := q.Identity().(*queryable)
+	nq.selection = []selector{}
+	for _, column := range columns {
+		switch cv := column.(type) {
+		case string:
+			if cv == "*" || cv == q.tableAlias()+".*" {
+				for _, c := range q.source.selectColumns() {
+					c = q.tableAlias() + strings.TrimPrefix(c, q.source.SqlName)
+					nq.selection = append(nq.selection, selector{Formula: c})
+				}
+				continue
+			}
+			if isIdentifier(cv) {
+				cv = q.tableAlias() + "." + cv
+			}
+			nq.selection = append(nq.selection, selector{Formula: cv})
+		case *Window:
+			if cv.Alias == "" {
+				nq.selection = append(nq.selection, selector{Formula: cv.Fragment(), Args: cv.Values()})
+			} else {
+				nq.selection = append(nq.selection, selector{Type: formula, Formula: cv.Fragment(), Alias: cv.Alias, Args: cv.Values()})
+			}
+		case SqlFunc:
+			nq.selection = append(nq.selection, selector{Formula: cv.Fragment(), Args: cv.Values()})
+		case SqlCol:
+			nq.selection = append(nq.selection, selector{Formula: cv.Fragment()})
+		}
+	}
+	return nq
+}
+
+// useSubqueries keeps the first error from any Scopes used as values so
+// that it is returned by the terminal functions of this Scope
+func (q *queryable) useSubqueries(vals ...interface{}) {
+	if q.err != nil {
+		return
+	}
+	for _, val := range vals {
+		if scope, ok := val.(Scope); ok {
+			if sq := scopeOf(scope); sq != nil && sq.err != nil {
+				q.err = sq.err
+				return
+			}
+		}
+	}
+}
+
+// isIdentifier returns whether a string is a plain column name
+func isIdentifier(s string) bool {
+	return s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+	}) == -1
+}
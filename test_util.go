@@ -52,6 +52,9 @@ func setupMysqlTestConn() *Connection {
 
 	p := conn.MustCreateMapper("Post", &post{})
 	u := conn.MustCreateMapper("User", &user{})
+	conn.MustCreateMapper("Article", &article{})
+	conn.MustCreateMapper("Label", &label{})
+	conn.MustCreateMapper("Member", &member{})
 	createDefaultPosts(p)
 	createDefaultUsers(u)
 
@@ -99,6 +102,9 @@ func setupPostgresTestConn() *Connection {
 	conn.Config = NewRailsConfig()
 	p := conn.MustCreateMapper("Post", &post{})
 	u := conn.MustCreateMapper("User", &user{})
+	conn.MustCreateMapper("Article", &article{})
+	conn.MustCreateMapper("Label", &label{})
+	conn.MustCreateMapper("Member", &member{})
 	createDefaultPosts(p)
 	createDefaultUsers(u)
 
@@ -120,6 +126,9 @@ func setupSqliteTestConn() *Connection {
 	conn.Config = NewRailsConfig()
 	p := conn.MustCreateMapper("Post", &post{})
 	u := conn.MustCreateMapper("User", &user{})
+	conn.MustCreateMapper("Article", &article{})
+	conn.MustCreateMapper("Label", &label{})
+	conn.MustCreateMapper("Member", &member{})
 	createDefaultPosts(p)
 	createDefaultUsers(u)
 
@@ -153,6 +162,30 @@ type post struct {
 	User      user
 	*Mixin
 }
+
+// articles and labels are a has and belongs to many relation through the
+// articles_labels table
+type article struct {
+	Id     int
+	Title  string
+	Labels []label
+	*Mixin
+}
+type label struct {
+	Id       int
+	Name     string
+	Articles []*article
+	*Mixin
+}
+
+// members are friends with other members through the friendships table
+type member struct {
+	Id      int
+	Name    string
+	Friends []member `db_join_table:"friendships" db_join_foreign_key:"friend_id"`
+	*Mixin
+}
+
 type user struct {
 	Id       int
 	Name     string
@@ -199,6 +232,39 @@ var mysqlCreateScript = []string{
 	"CREATE UNIQUE INDEX `unique_id` USING BTREE ON `users`( `id` );\n",
 	"CREATE UNIQUE INDEX `unique_name` USING BTREE ON `users`( `name` );\n",
 	"INSERT INTO `users` (`id`,`email`,`password`,`name`) VALUES ('1','user@example.com', 'id10t', 'wat');",
+	"DROP TABLE IF EXISTS `articles` CASCADE;\n",
+	"CREATE TABLE `articles` ( \n" +
+		"	`id` Int( 255 ) UNSIGNED AUTO_INCREMENT NOT NULL, \n" +
+		"	`title` VarChar( 255 ) CHARACTER SET utf8 COLLATE utf8_general_ci NOT NULL, \n" +
+		"	 PRIMARY KEY ( `id` )\n" +
+		" )\n" +
+		"ENGINE = InnoDB;\n",
+	"DROP TABLE IF EXISTS `labels` CASCADE;\n",
+	"CREATE TABLE `labels` ( \n" +
+		"	`id` Int( 255 ) UNSIGNED AUTO_INCREMENT NOT NULL, \n" +
+		"	`name` VarChar( 255 ) CHARACTER SET utf8 COLLATE utf8_general_ci NOT NULL, \n" +
+		"	 PRIMARY KEY ( `id` )\n" +
+		" )\n" +
+		"ENGINE = InnoDB;\n",
+	"DROP TABLE IF EXISTS `articles_labels` CASCADE;\n",
+	"CREATE TABLE `articles_labels` ( \n" +
+		"	`article_id` Int( 255 ) UNSIGNED NOT NULL, \n" +
+		"	`label_id` Int( 255 ) UNSIGNED NOT NULL\n" +
+		" )\n" +
+		"ENGINE = InnoDB;\n",
+	"DROP TABLE IF EXISTS `members` CASCADE;\n",
+	"CREATE TABLE `members` ( \n" +
+		"	`id` Int( 255 ) UNSIGNED AUTO_INCREMENT NOT NULL, \n" +
+		"	`name` VarChar( 255 ) CHARACTER SET utf8 COLLATE utf8_general_ci NOT NULL, \n" +
+		"	 PRIMARY KEY ( `id` )\n" +
+		" )\n" +
+		"ENGINE = InnoDB;\n",
+	"DROP TABLE IF EXISTS `friendships` CASCADE;\n",
+	"CREATE TABLE `friendships` ( \n" +
+		"	`member_id` Int( 255 ) UNSIGNED NOT NULL, \n" +
+		"	`friend_id` Int( 255 ) UNSIGNED NOT NULL\n" +
+		" )\n" +
+		"ENGINE = InnoDB;\n",
 }
 
 var sqliteCreateScript = []string{
@@ -232,6 +298,27 @@ var sqliteCreateScript = []string{
 	`CREATE INDEX "index_id1" ON "users"( "id" );`,
 
 	`INSERT INTO "users" ("id", "email","password","name") VALUES (1, 'user@example.com', 'id10t', 'wat');`,
+
+	`DROP TABLE IF EXISTS "articles";`,
+	`CREATE TABLE "articles"(
+    "id" Integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    "title" Text NOT NULL );`,
+	`DROP TABLE IF EXISTS "labels";`,
+	`CREATE TABLE "labels"(
+    "id" Integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    "name" Text NOT NULL );`,
+	`DROP TABLE IF EXISTS "articles_labels";`,
+	`CREATE TABLE "articles_labels"(
+    "article_id" Integer NOT NULL,
+    "label_id" Integer NOT NULL );`,
+	`DROP TABLE IF EXISTS "members";`,
+	`CREATE TABLE "members"(
+    "id" Integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+    "name" Text NOT NULL );`,
+	`DROP TABLE IF EXISTS "friendships";`,
+	`CREATE TABLE "friendships"(
+    "member_id" Integer NOT NULL,
+    "friend_id" Integer NOT NULL );`,
 }
 
 var postgresCreateScript = []string{
@@ -265,4 +352,33 @@ var postgresCreateScript = []string{
   );`,
 	`ALTER TABLE "users" OWNER TO postgres;`,
 	`INSERT INTO "users" (email,password,name) VALUES ('user@example.com', 'id10t', 'wat');`,
+
+	`DROP TABLE IF EXISTS "articles";`,
+	`CREATE TABLE "articles"(
+    id bigserial NOT NULL,
+    title character varying(255) NOT NULL,
+    CONSTRAINT pk_articles PRIMARY KEY (id)
+  );`,
+	`DROP TABLE IF EXISTS "labels";`,
+	`CREATE TABLE "labels"(
+    id bigserial NOT NULL,
+    name character varying(255) NOT NULL,
+    CONSTRAINT pk_labels PRIMARY KEY (id)
+  );`,
+	`DROP TABLE IF EXISTS "articles_labels";`,
+	`CREATE TABLE "articles_labels"(
+    article_id integer NOT NULL,
+    label_id integer NOT NULL
+  );`,
+	`DROP TABLE IF EXISTS "members";`,
+	`CREATE TABLE "members"(
+    id bigserial NOT NULL,
+    name character varying(255) NOT NULL,
+    CONSTRAINT pk_members PRIMARY KEY (id)
+  );`,
+	`DROP TABLE IF EXISTS "friendships";`,
+	`CREATE TABLE "friendships"(
+    member_id integer NOT NULL,
+    friend_id integer NOT NULL
+  );`,
 }
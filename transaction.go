@@ -0,0 +1,236 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrNotInTransaction is returned when Commit or Rollback is called on a
+// Connection that was not created by Begin, or that has already been
+// committed or rolled back.
+var ErrNotInTransaction = errors.New("Connection is not in a transaction")
+
+/*
+Begin starts a transaction and returns an EphemeralConnection for it. The
+EphemeralConnection is a *Connection that shares the Dialect, Config, mapped
+structs and QueryCache of the Connection it was started from, but every query
+that it runs goes through the *sql.Tx. Mappers, MapperPlus's and Scopes can
+be bound to the EphemeralConnection with the Mapper and Scope functions, and
+struct instances retrieved through them will Save and Delete inside the
+transaction as well.
+
+  tx, e := conn.Begin()
+  if e != nil {
+    return e
+  }
+  TxPosts := tx.Mapper(Posts)
+  TxPosts.SaveAll(&newPost)
+  tx.Scope(oldPosts).UpdateAttribute("archived", true)
+  e = tx.Commit()
+
+Once the transaction is committed or rolled back, the EphemeralConnection will
+go back to running queries on the database connection directly.
+
+Calling Begin on an EphemeralConnection will start a nested transaction using
+a SAVEPOINT. Committing the nested transaction releases the savepoint, while
+rolling it back only undoes the changes made since the savepoint, so the outer
+transaction can continue to be used.
+
+  inner, e := tx.Begin()
+  ...
+  if e != nil {
+    // tx is still usable
+    inner.Rollback()
+  }
+*/
+func (c *Connection) Begin() (*Connection, error) {
+	return c.BeginContext(context.Background())
+}
+
+// BeginContext is Begin with a context, if the context is cancelled before
+// the transaction is committed, the transaction will be rolled back.
+func (c *Connection) BeginContext(ctx context.Context) (*Connection, error) {
+	if c.currentTx() != nil {
+		return c.nested(ctx)
+	}
+	tx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ec := c.ephemeral(tx)
+	ec.savepoints = new(int)
+	return ec, nil
+}
+
+func (c *Connection) ephemeral(tx *sql.Tx) *Connection {
+	ec := new(Connection)
+	*ec = *c
+	ec.tx = tx
+	ec.parent = c
+	ec.sources = make(map[string]*source)
+
+	return ec
+}
+
+func (c *Connection) nested(ctx context.Context) (*Connection, error) {
+	ec := c.ephemeral(c.currentTx())
+	// the count of savepoints is shared by the whole transaction, so
+	// nested transactions at the same depth get their own savepoint
+	*ec.savepoints++
+	ec.savepoint = fmt.Sprintf("db_savepoint_%d", *ec.savepoints)
+	_, err := ec.tx.ExecContext(ctx, c.Dialect.Savepoint(ec.savepoint))
+	if err != nil {
+		return nil, err
+	}
+
+	return ec, nil
+}
+
+// Commit the transaction started by Begin, for nested transactions
+// this will release the savepoint
+func (c *Connection) Commit() error {
+	if c.tx == nil {
+		return ErrNotInTransaction
+	}
+	var err error
+	if c.savepoint != "" {
+		_, err = c.tx.Exec(c.Dialect.ReleaseSavepoint(c.savepoint))
+	} else {
+		err = c.tx.Commit()
+	}
+	c.tx = nil
+
+	return err
+}
+
+// Rollback the transaction started by Begin, for nested transactions
+// this will rollback to the savepoint and then release it
+func (c *Connection) Rollback() error {
+	if c.tx == nil {
+		return ErrNotInTransaction
+	}
+	var err error
+	if c.savepoint != "" {
+		_, err = c.tx.Exec(c.Dialect.RollbackToSavepoint(c.savepoint))
+		if err == nil {
+			_, err = c.tx.Exec(c.Dialect.ReleaseSavepoint(c.savepoint))
+		}
+	} else {
+		err = c.tx.Rollback()
+	}
+	c.tx = nil
+
+	return err
+}
+
+// InTransaction returns whether queries on the Connection are currently
+// being run inside of a transaction.
+func (c *Connection) InTransaction() bool {
+	return c.currentTx() != nil
+}
+
+/*
+Transaction runs f inside of a transaction. If f returns nil, the transaction
+is committed, if f returns an error or panics, the transaction is rolled back.
+Errors are returned to the caller and panics are re-panicked after the
+rollback. When called on an EphemeralConnection, f is run inside of a nested
+transaction. If the Connection has a RetryPolicy and the transaction fails from
+an error the Dialect considers retryable, like a deadlock, f is run again in a
+new transaction, so f should be safe to run more than once.
+
+  err := conn.Transaction(func(tx *db.Connection) error {
+    e := tx.Mapper(Posts).SaveAll(&post)
+    if e != nil {
+      return e
+    }
+    return tx.Scope(author).UpdateAttribute("post_count", count+1)
+  })
+*/
+func (c *Connection) Transaction(f func(tx *Connection) error) error {
+	if c.currentTx() != nil {
+		return c.transaction(f)
+	}
+	return c.retry(context.Background(), func() error {
+		return c.transaction(f)
+	})
+}
+
+func (c *Connection) transaction(f func(tx *Connection) error) error {
+	tx, err := c.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	err = f(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Mapper returns a version of the Mapper or MapperPlus that will run its
+// queries on this Connection. It is meant to be used with the Connections
+// returned by Begin and Transaction.
+//
+//  TxUsers := tx.Mapper(Users)
+func (c *Connection) Mapper(m Mapper) Mapper {
+	switch mv := m.(type) {
+	case *source:
+		return c.bind(mv)
+	case *mapperPlus:
+		return c.bindPlus(mv)
+	}
+	return m
+}
+
+// Scope returns a copy of the Scope that will run on this Connection. It is
+// meant to be used with the Connections returned by Begin and Transaction.
+//
+//  tx.Scope(expiredPosts).Delete()
+func (c *Connection) Scope(s Scope) Scope {
+	switch sv := s.(type) {
+	case *queryable:
+		nq := sv.Identity().(*queryable)
+		nq.source = c.bind(sv.source)
+		return nq
+	case *mapperPlus:
+		return c.bindPlus(sv)
+	}
+	return s
+}
+
+func (c *Connection) bindPlus(mp *mapperPlus) *mapperPlus {
+	nmp := &mapperPlus{source: c.bind(mp.source)}
+	if mp.query != nil {
+		nmp.query = c.Scope(mp.query)
+	}
+	return nmp
+}
+
+// bind returns a copy of the source that runs its queries on c, copies
+// are kept so each Mapper is only copied once per Connection
+func (c *Connection) bind(s *source) *source {
+	if s.conn == c {
+		return s
+	}
+	if bs, ok := c.sources[s.Name]; ok && bs.FullName == s.FullName {
+		return bs
+	}
+	bs := new(source)
+	*bs = *s
+	bs.conn = c
+	c.sources[s.Name] = bs
+
+	return bs
+}
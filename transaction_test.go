@@ -0,0 +1,207 @@
+package db
+
+import (
+	"errors"
+	. "github.com/acsellers/assert"
+	"strings"
+	"testing"
+)
+
+func TestTransaction(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+
+			test.Section("Rollback on error")
+			e := c.Transaction(func(tx *Connection) error {
+				test.IsTrue(tx.InTransaction())
+				test.NoError(tx.Mapper(Posts).SaveAll(&post{
+					Title:     "Rolled Back",
+					Permalink: "rolled_back",
+					Body:      "Never saved",
+				}))
+				return errors.New("rollback")
+			})
+			test.AreEqual("rollback", e.Error())
+			ct, e := Posts.Count()
+			test.NoError(e)
+			test.AreEqual(2, ct)
+
+			test.Section("Rollback on panic")
+			func() {
+				defer func() { recover() }()
+				c.Transaction(func(tx *Connection) error {
+					tx.Scope(Posts.EqualTo("id", 1)).UpdateAttribute("title", "Panicked")
+					panic("panic")
+				})
+			}()
+			ct, e = Posts.EqualTo("title", "Panicked").Count()
+			test.NoError(e)
+			test.AreEqual(0, ct)
+
+			test.Section("Commit")
+			var p post
+			test.NoError(c.Transaction(func(tx *Connection) error {
+				e := tx.Mapper(Posts).Find(1, &p)
+				if e != nil {
+					return e
+				}
+				p.Title = "Committed"
+				return p.Save()
+			}))
+			ct, e = Posts.EqualTo("title", "Committed").Count()
+			test.NoError(e)
+			test.AreEqual(1, ct)
+
+			test.Section("Mixin outside transaction after commit")
+			p.Title = "First Post"
+			test.NoError(p.Save())
+			test.IsFalse(c.InTransaction())
+			test.AreEqual(ErrNotInTransaction, c.Commit())
+		}
+	})
+}
+
+func TestRowLocks(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+
+			test.Section("Locking clauses")
+			test.AreEqual("FOR UPDATE", Posts.ForUpdate().LockSql())
+			test.AreEqual("FOR SHARE NOWAIT", Posts.ForShare().NoWait().LockSql())
+			test.AreEqual("FOR UPDATE SKIP LOCKED", Posts.SkipLocked().LockSql())
+			test.AreEqual("", Posts.EqualTo("id", 1).LockSql())
+
+			test.Section("Locking outside of a transaction")
+			var posts []post
+			test.IsNotNil(Posts.ForUpdate().RetrieveAll(&posts))
+			_, e := Posts.ForUpdate().Count()
+			test.IsNotNil(e)
+			_, e = Posts.ForShare().Exists()
+			test.IsNotNil(e)
+			var total int64
+			test.IsNotNil(Posts.ForUpdate().Sum("views", &total))
+
+			test.Section("Locking inside of a transaction")
+			test.NoError(c.Transaction(func(tx *Connection) error {
+				TxPosts := tx.Mapper(Posts)
+				if e := TxPosts.Order("id").ForUpdate().SkipLocked().RetrieveAll(&posts); e != nil {
+					return e
+				}
+				test.AreEqual(2, len(posts))
+				ct, e := TxPosts.ForUpdate().Count()
+				test.AreEqual(2, ct)
+				if e != nil {
+					return e
+				}
+				found, e := TxPosts.ForUpdate().EqualTo("id", 1).Exists()
+				test.IsTrue(found)
+				return e
+			}))
+		}
+
+		test.Section("Unsupported locks")
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+			query, _ := Base{Dialect: noLockDialect{c.Dialect}}.Query(Posts.ForUpdate())
+			test.IsTrue(strings.HasSuffix(query, " FOR UPDATE"))
+			test.NoError(c.Transaction(func(tx *Connection) error {
+				tx.Dialect = noLockDialect{tx.Dialect}
+				TxPosts := tx.Mapper(Posts)
+				var posts []post
+				test.IsNotNil(TxPosts.ForUpdate().RetrieveAll(&posts))
+				_, e := TxPosts.ForUpdate().Count()
+				test.IsNotNil(e)
+				var total int64
+				test.IsNotNil(TxPosts.ForUpdate().Sum("views", &total))
+				return nil
+			}))
+		}
+
+		test.Section("Postgres locking")
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+			pg := newPostgres()
+			query, _ := pg.Query(Posts.EqualTo("id", 1).Limit(1).ForUpdate().NoWait())
+			test.IsTrue(strings.HasSuffix(query, " LIMIT 1 FOR UPDATE NOWAIT"))
+			_, e := pg.Lock(Posts.GroupBy("user_id").ForUpdate())
+			test.IsNotNil(e)
+			_, e = pg.Lock(Posts.Union(Posts.EqualTo("id", 1)).ForShare())
+			test.IsNotNil(e)
+
+			lock, e := newSqlite().Lock(Posts.ForUpdate())
+			test.NoError(e)
+			test.AreEqual("", lock)
+		}
+	})
+}
+
+func TestNestedTransaction(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, c := range availableTestConns() {
+			Posts := c.m("Post")
+
+			test.NoError(c.Transaction(func(tx *Connection) error {
+				TxPosts := tx.Mapper(Posts)
+				e := TxPosts.EqualTo("id", 1).UpdateAttribute("title", "Outer")
+				if e != nil {
+					return e
+				}
+
+				test.Section("Failing inner transaction")
+				e = tx.Transaction(func(inner *Connection) error {
+					test.NoError(inner.Mapper(Posts).EqualTo("id", 2).UpdateAttribute("title", "Inner"))
+					return errors.New("inner failure")
+				})
+				test.AreEqual("inner failure", e.Error())
+
+				test.Section("Outer transaction is still usable")
+				ct, e := TxPosts.EqualTo("title", "Inner").Count()
+				test.NoError(e)
+				test.AreEqual(0, ct)
+				ct, e = TxPosts.EqualTo("title", "Outer").Count()
+				test.NoError(e)
+				test.AreEqual(1, ct)
+
+				test.Section("Sibling savepoints")
+				first, e := tx.Begin()
+				test.NoError(e)
+				test.NoError(first.Commit())
+				second, e := tx.Begin()
+				test.NoError(e)
+				test.IsTrue(first.savepoint != second.savepoint)
+				test.NoError(second.Rollback())
+
+				test.Section("Successful inner transaction")
+				return tx.Transaction(func(inner *Connection) error {
+					return inner.Mapper(Posts).EqualTo("id", 1).UpdateAttribute("title", "First Post")
+				})
+			}))
+
+			ct, e := Posts.EqualTo("title", "First Post").Count()
+			test.NoError(e)
+			test.AreEqual(1, ct)
+		}
+	})
+}
+
+func TestSavepointSql(t *testing.T) {
+	Within(t, func(test *Test) {
+		for _, name := range []string{"mysql", "postgres", "sqlite3"} {
+			d := registeredDialects[name]
+			test.AreEqual("SAVEPOINT db_savepoint_1", d.Savepoint("db_savepoint_1"))
+			test.AreEqual("RELEASE SAVEPOINT db_savepoint_1", d.ReleaseSavepoint("db_savepoint_1"))
+			test.AreEqual("ROLLBACK TO SAVEPOINT db_savepoint_1", d.RollbackToSavepoint("db_savepoint_1"))
+		}
+	})
+}
+
+// noLockDialect is a Dialect for a database that can't lock rows
+type noLockDialect struct {
+	Dialect
+}
+
+func (d noLockDialect) Lock(scope Scope) (string, error) {
+	return "", errors.New("row locks are not supported")
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"strings"
+)
+
+/*
+A Window is a SQL function with an OVER clause, like ROW_NUMBER() or a SUM
+that keeps a running total. Windows are made from SqlFuncs with Over, and
+can be used anywhere a SqlFunc can be used, in Columns to select them and in
+Order. The values of the function are bound to the query.
+
+  // number each user's posts from newest to oldest
+  rank := db.Func("ROW_NUMBER()").Over("user_id", "created_at DESC").As("rank")
+  Posts.Columns("*", rank)
+
+  // a running total of views
+  db.Func("SUM(views)").Over("", "id").Rows("UNBOUNDED PRECEDING", "CURRENT ROW")
+*/
+type Window struct {
+	// The function that is applied over the window
+	Function SqlFunc
+	// The PARTITION BY and ORDER BY of the window, either may be blank
+	Partition string
+	Order     string
+	// The frame of the window, like ROWS BETWEEN 2 PRECEDING AND CURRENT ROW
+	Frame string
+	// The name of the column when the Window is selected with Columns, the
+	// value will be put in the struct field for that column
+	Alias string
+}
+
+// Over turns a SQL function into a window function, partitioned and ordered
+// by the partition and order SQL, either of which may be blank
+func (sf sqlFunc) Over(partition, order string) *Window {
+	return &Window{Function: sf, Partition: partition, Order: order}
+}
+
+// Over returns a copy of the Window with a different partition and order
+func (w *Window) Over(partition, order string) *Window {
+	nw := *w
+	nw.Partition, nw.Order = partition, order
+	return &nw
+}
+
+// Rows returns a copy of the Window with a frame of the rows between start
+// and end, like Rows("UNBOUNDED PRECEDING", "CURRENT ROW")
+func (w *Window) Rows(start, end string) *Window {
+	nw := *w
+	nw.Frame = "ROWS BETWEEN " + start + " AND " + end
+	return &nw
+}
+
+// As returns a copy of the Window that will be selected as the column alias
+func (w *Window) As(alias string) *Window {
+	nw := *w
+	nw.Alias = alias
+	return &nw
+}
+
+func (w *Window) Fragment() string {
+	clauses := []string{}
+	if w.Partition != "" {
+		clauses = append(clauses, "PARTITION BY "+w.Partition)
+	}
+	if w.Order != "" {
+		clauses = append(clauses, "ORDER BY "+w.Order)
+	}
+	if w.Frame != "" {
+		clauses = append(clauses, w.Frame)
+	}
+	return w.Function.Fragment() + " OVER (" + strings.Join(clauses, " ") + ")"
+}
+func (w *Window) Values() []interface{} {
+	return w.Function.Values()
+}
+func (w *Window) String() string {
+	return withVars(w.Fragment(), w.Values())
+}